@@ -2,25 +2,33 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+	"github.com/warm3snow/WorkWise/internal/analytics"
 	"github.com/warm3snow/WorkWise/internal/config"
 	"github.com/warm3snow/WorkWise/internal/llm"
+	mcpclient "github.com/warm3snow/WorkWise/internal/mcp"
 )
 
 // Agent represents the AI agent using eino framework
 type Agent struct {
 	config  *config.Config
 	llm     model.ChatModel
+	mcp     *mcpclient.Manager // nil unless Extensions.MCPEnabled
+	tools   *ToolRegistry
+	tracker *analytics.Tracker // nil unless SetTracker is called
 	history []*schema.Message
 }
 
 // NewAgent creates a new AI agent
 func NewAgent(cfg *config.Config) (*Agent, error) {
 	// Initialize LLM client using eino-ext
-	llmClient, err := llm.NewClient(cfg)
+	llmClient, mcpMgr, err := llm.NewClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM client: %w", err)
 	}
@@ -28,6 +36,8 @@ func NewAgent(cfg *config.Config) (*Agent, error) {
 	agent := &Agent{
 		config:  cfg,
 		llm:     llmClient,
+		mcp:     mcpMgr,
+		tools:   NewToolRegistryFromMCP(mcpMgr),
 		history: make([]*schema.Message, 0),
 	}
 
@@ -39,27 +49,138 @@ func NewAgent(cfg *config.Config) (*Agent, error) {
 	return agent, nil
 }
 
-// Process processes a user message and returns the agent's response
-func (a *Agent) Process(ctx context.Context, userMessage string) (string, error) {
+// SetTracker attaches a behavior Tracker that receives a BehaviorToolCall
+// event for every tool dispatched during Process's ReAct loop. Pass nil to
+// detach it.
+func (a *Agent) SetTracker(tracker *analytics.Tracker) {
+	a.tracker = tracker
+}
+
+// Close disconnects the agent's MCP servers, if any were connected. Callers
+// that replace or discard an Agent (config reload, app shutdown) must call
+// this on the old instance or its MCP subprocesses leak.
+func (a *Agent) Close(ctx context.Context) error {
+	if a.mcp == nil {
+		return nil
+	}
+	return a.mcp.Close(ctx)
+}
+
+// Process processes a user message and returns the agent's response along
+// with token usage for the final LLM call. This is a bounded ReAct-style
+// loop: when the model responds with tool_use calls, Process dispatches
+// each one to the matching Tool in a.tools, feeds the tool_result back as a
+// schema.ToolMessage, and repeats until the model returns a plain assistant
+// message or MaxIterations is hit.
+func (a *Agent) Process(ctx context.Context, userMessage string) (string, llm.Usage, error) {
 	// Add user message to history
 	a.addToHistory(schema.UserMessage(userMessage))
 
-	// Prepare messages for the LLM
-	messages := a.getRecentHistory()
+	maxIterations := a.config.AI.Agent.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = 1
+	}
+
+	for i := 0; i < maxIterations; i++ {
+		resp, err := a.llm.Generate(ctx, a.getRecentHistory())
+		if err != nil {
+			return "", llm.Usage{}, fmt.Errorf("failed to generate response: %w", err)
+		}
+
+		if len(resp.ToolCalls) == 0 || a.tools.Len() == 0 {
+			a.addToHistory(schema.AssistantMessage(resp.Content, nil))
+			return resp.Content, llm.UsageFromMessage(resp), nil
+		}
+
+		a.addToHistory(resp)
+		for _, call := range resp.ToolCalls {
+			result := a.invokeTool(ctx, call.Function.Name, []byte(call.Function.Arguments))
+			a.addToHistory(schema.ToolMessage(result, call.ID))
+		}
+	}
+
+	return "", llm.Usage{}, fmt.Errorf("exceeded max iterations (%d) without a final response", maxIterations)
+}
+
+// invokeTool dispatches a single tool call to the registered Tool, tracking
+// its duration and outcome. Errors (including an unknown tool name) are
+// returned as plain text so the model can see and react to them rather than
+// aborting the whole ReAct loop.
+func (a *Agent) invokeTool(ctx context.Context, name string, args json.RawMessage) string {
+	start := time.Now()
+
+	tool, ok := a.tools.Get(name)
+	if !ok {
+		err := fmt.Errorf("no tool registered with name %q", name)
+		a.trackToolCall(name, time.Since(start), false)
+		return fmt.Sprintf("error: %v", err)
+	}
+
+	result, err := tool.Invoke(ctx, args)
+	success := err == nil
+	a.trackToolCall(name, time.Since(start), success)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return result
+}
 
-	// Call the LLM
-	resp, err := a.llm.Generate(ctx, messages)
+// trackToolCall records a BehaviorToolCall event if a Tracker is attached.
+func (a *Agent) trackToolCall(name string, duration time.Duration, success bool) {
+	if a.tracker == nil {
+		return
+	}
+	a.tracker.TrackToolCall(name, duration, success, a.sessionID())
+}
+
+// sessionID returns the tracker's current session ID, or "" if no tracker
+// is attached.
+func (a *Agent) sessionID() string {
+	if a.tracker == nil {
+		return ""
+	}
+	return a.tracker.GetSessionID()
+}
+
+// StreamChunk is a single token-level piece of a ProcessStream response.
+type StreamChunk struct {
+	Content string
+	Usage   llm.Usage
+}
+
+// ProcessStream processes a user message like Process, but returns a
+// channel of StreamChunks as they arrive instead of waiting for the full
+// response. It does not participate in MCP tool-calling: a streamed reply
+// is assumed to be the model's final plain-text answer, so tool_use turns
+// are out of scope here (Process remains the place to go for tool
+// dispatch). The returned channel closes once generation finishes or ctx
+// is canceled; the last StreamChunk received carries the cumulative usage
+// seen so far, which callers can use to report how much was generated
+// before a cancellation. The assistant's partial-or-complete reply is
+// appended to history once the channel closes.
+func (a *Agent) ProcessStream(ctx context.Context, userMessage string) (<-chan StreamChunk, error) {
+	a.addToHistory(schema.UserMessage(userMessage))
+
+	streamer := llm.NewStreamingClient(a.llm)
+	chunks, err := streamer.Stream(ctx, a.getRecentHistory())
 	if err != nil {
-		return "", fmt.Errorf("failed to generate response: %w", err)
+		return nil, fmt.Errorf("failed to start streaming generation: %w", err)
 	}
 
-	// Extract response content
-	responseContent := resp.Content
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		for chunk := range chunks {
+			content.WriteString(chunk.Content)
+			out <- StreamChunk{Content: chunk.Content, Usage: chunk.Usage}
+		}
 
-	// Add assistant response to history
-	a.addToHistory(schema.AssistantMessage(responseContent, nil))
+		a.addToHistory(schema.AssistantMessage(content.String(), nil))
+	}()
 
-	return responseContent, nil
+	return out, nil
 }
 
 // addToHistory adds a message to the conversation history
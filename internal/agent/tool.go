@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+	mcpclient "github.com/warm3snow/WorkWise/internal/mcp"
+)
+
+// Tool is a single callable capability the agent's ReAct loop can dispatch
+// to in response to a model tool_use turn.
+type Tool interface {
+	// Name is the tool's identifier, matching schema.ToolCall.Function.Name.
+	Name() string
+
+	// Schema describes the tool for the model, including its parameters.
+	Schema() *schema.ToolInfo
+
+	// Invoke executes the tool with the given arguments and returns its
+	// result as text to be fed back to the model as a tool message.
+	Invoke(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// ToolRegistry holds the Tools available to an Agent's ReAct loop, keyed by
+// name. Get and Len on a nil *ToolRegistry behave as on an empty one.
+type ToolRegistry struct {
+	tools map[string]Tool
+}
+
+// NewToolRegistry creates an empty ToolRegistry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]Tool)}
+}
+
+// NewToolRegistryFromMCP builds a ToolRegistry from every tool exposed by
+// mgr's connected MCP servers. It returns an empty registry if mgr is nil,
+// so callers can build one unconditionally regardless of whether
+// Extensions.MCPEnabled is set.
+func NewToolRegistryFromMCP(mgr *mcpclient.Manager) *ToolRegistry {
+	registry := NewToolRegistry()
+	if mgr == nil {
+		return registry
+	}
+
+	for _, info := range mgr.ToolInfos() {
+		registry.Register(&mcpTool{mgr: mgr, info: info})
+	}
+
+	return registry
+}
+
+// Register adds tool to the registry, keyed by its Name(). A later
+// registration with the same name replaces the earlier one.
+func (r *ToolRegistry) Register(tool Tool) {
+	r.tools[tool.Name()] = tool
+}
+
+// Get returns the tool registered under name, or false if none is.
+func (r *ToolRegistry) Get(name string) (Tool, bool) {
+	if r == nil {
+		return nil, false
+	}
+	tool, ok := r.tools[name]
+	return tool, ok
+}
+
+// Len reports how many tools are registered.
+func (r *ToolRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.tools)
+}
+
+// mcpTool adapts a single tool exposed by an MCP server to the Tool
+// interface, routing Invoke back through the owning Manager.
+type mcpTool struct {
+	mgr  *mcpclient.Manager
+	info *schema.ToolInfo
+}
+
+func (t *mcpTool) Name() string { return t.info.Name }
+
+func (t *mcpTool) Schema() *schema.ToolInfo { return t.info }
+
+func (t *mcpTool) Invoke(ctx context.Context, args json.RawMessage) (string, error) {
+	result, err := t.mgr.CallTool(ctx, t.info.Name, args)
+	if err != nil {
+		return "", fmt.Errorf("tool %q: %w", t.info.Name, err)
+	}
+	return result, nil
+}
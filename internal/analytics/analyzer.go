@@ -1,8 +1,8 @@
 package analytics
 
 import (
-	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,63 +12,95 @@ import (
 
 // Statistics represents aggregated behavior statistics
 type Statistics struct {
-	Period            string         `json:"period"`
-	TotalQueries      int            `json:"total_queries"`
-	TotalResponses    int            `json:"total_responses"`
-	TotalCommands     int            `json:"total_commands"`
-	TotalErrors       int            `json:"total_errors"`
-	TotalSessions     int            `json:"total_sessions"`
-	AvgResponseTime   time.Duration  `json:"avg_response_time"`
-	AvgSessionTime    time.Duration  `json:"avg_session_time"`
-	TotalTokens       int            `json:"total_tokens"`
-	TopCommands       map[string]int `json:"top_commands"`
-	HourlyActivity    map[int]int    `json:"hourly_activity"`
-	DailyActivity     map[string]int `json:"daily_activity"`
-	ErrorRate         float64        `json:"error_rate"`
-	MostActiveHour    int            `json:"most_active_hour"`
-	ProductivityScore float64        `json:"productivity_score"`
+	Period              string         `json:"period"`
+	TotalQueries        int            `json:"total_queries"`
+	TotalResponses      int            `json:"total_responses"`
+	TotalCommands       int            `json:"total_commands"`
+	TotalErrors         int            `json:"total_errors"`
+	TotalSessions       int            `json:"total_sessions"`
+	AvgResponseTime     time.Duration  `json:"avg_response_time"`
+	AvgTimeToFirstToken time.Duration  `json:"avg_time_to_first_token"`
+	AvgSessionTime      time.Duration  `json:"avg_session_time"`
+	TotalTokens         int            `json:"total_tokens"`
+	EstimatedCostUSD    float64        `json:"estimated_cost_usd"`
+	TopCommands         map[string]int `json:"top_commands"`
+	TopTools            map[string]int `json:"top_tools"`
+	TopSkills           map[string]int `json:"top_skills"`
+	HourlyActivity      map[int]int    `json:"hourly_activity"`
+	DailyActivity       map[string]int `json:"daily_activity"`
+	ErrorRate           float64        `json:"error_rate"`
+	MostActiveHour      int            `json:"most_active_hour"`
+	ProductivityScore   float64        `json:"productivity_score"`
+	Anomalies           []Anomaly      `json:"anomalies,omitempty"`
+	RedactionActive     bool           `json:"redaction_active,omitempty"`
 }
 
 // Analyzer analyzes user behavior data
 type Analyzer struct {
-	dataPath string
+	store Store
+
+	// baselinePath is where per-metric EWMA baselines are persisted
+	// (<dataPath>/baselines.json). Empty disables persistence: baselines
+	// are still scored and updated in-memory for the duration of a single
+	// AnalyzePeriod call, but don't carry over to the next one.
+	baselinePath string
+
+	// privacyActive mirrors whether the Tracker writing these events has
+	// an active EventSanitizer, so Statistics can report it back via
+	// FormatStatistics. Set with SetPrivacyActive.
+	privacyActive bool
 }
 
-// NewAnalyzer creates a new behavior analyzer
+// SetPrivacyActive records whether tracked content is being truncated,
+// redacted, or hashed before persistence, so subsequent AnalyzePeriod
+// results carry that fact through to FormatStatistics. Callers typically
+// pass sanitizer.Active() for the same EventSanitizer given to the Tracker
+// writing the events this Analyzer reads.
+func (a *Analyzer) SetPrivacyActive(active bool) {
+	a.privacyActive = active
+}
+
+// NewAnalyzer creates a behavior analyzer backed by the default
+// JSONLStore over dataPath.
 func NewAnalyzer(dataPath string) *Analyzer {
 	return &Analyzer{
-		dataPath: dataPath,
+		store:        NewJSONLStore(JSONLStoreConfig{DataPath: dataPath}),
+		baselinePath: filepath.Join(dataPath, "baselines.json"),
 	}
 }
 
-// AnalyzePeriod analyzes behaviors for a specific period
-func (a *Analyzer) AnalyzePeriod(startDate, endDate time.Time) (*Statistics, error) {
-	// Load all events in the period
-	events, err := a.loadEventsByPeriod(startDate, endDate)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load events: %w", err)
-	}
-
-	if len(events) == 0 {
-		return &Statistics{
-			Period: fmt.Sprintf("%s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")),
-		}, nil
-	}
+// NewAnalyzerWithStore creates a behavior analyzer backed by an arbitrary
+// Store (e.g. a BoltStore produced by `workwise analytics migrate`).
+// Anomaly baselines aren't persisted for analyzers created this way, since
+// there's no guaranteed on-disk path to store them alongside; use
+// NewAnalyzer if you need baselines to persist across runs.
+func NewAnalyzerWithStore(store Store) *Analyzer {
+	return &Analyzer{store: store}
+}
 
+// AnalyzePeriod analyzes behaviors for a specific period. It streams
+// events from the underlying Store through a rolling aggregator instead of
+// loading the whole period into memory first, so month/year queries stay
+// bounded in RAM regardless of how many events they cover.
+func (a *Analyzer) AnalyzePeriod(startDate, endDate time.Time) (*Statistics, error) {
 	stats := &Statistics{
-		Period:         fmt.Sprintf("%s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")),
-		TopCommands:    make(map[string]int),
-		HourlyActivity: make(map[int]int),
-		DailyActivity:  make(map[string]int),
+		Period:          fmt.Sprintf("%s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")),
+		TopCommands:     make(map[string]int),
+		TopTools:        make(map[string]int),
+		TopSkills:       make(map[string]int),
+		HourlyActivity:  make(map[int]int),
+		DailyActivity:   make(map[string]int),
+		RedactionActive: a.privacyActive,
 	}
 
 	var totalResponseTime time.Duration
+	var totalTimeToFirstToken time.Duration
 	var totalSessionTime time.Duration
-	var responseCount int
-	var sessionCount int
+	var responseCount, firstTokenCount, sessionCount, eventCount int
+
+	for event := range a.store.Between(startDate, endDate) {
+		eventCount++
 
-	// Analyze each event
-	for _, event := range events {
 		switch event.Type {
 		case BehaviorQuery:
 			stats.TotalQueries++
@@ -78,7 +110,12 @@ func (a *Analyzer) AnalyzePeriod(startDate, endDate time.Time) (*Statistics, err
 				totalResponseTime += event.Metadata.Duration
 				responseCount++
 			}
+			if event.Metadata.TimeToFirstTokenMs > 0 {
+				totalTimeToFirstToken += time.Duration(event.Metadata.TimeToFirstTokenMs) * time.Millisecond
+				firstTokenCount++
+			}
 			stats.TotalTokens += event.Metadata.TokenCount
+			stats.EstimatedCostUSD += event.Metadata.EstimatedCostUSD
 		case BehaviorCommand:
 			stats.TotalCommands++
 			if event.Metadata.CommandName != "" {
@@ -90,6 +127,14 @@ func (a *Analyzer) AnalyzePeriod(startDate, endDate time.Time) (*Statistics, err
 				totalSessionTime += event.Metadata.Duration
 				sessionCount++
 			}
+		case BehaviorToolCall:
+			if event.Metadata.ToolName != "" {
+				stats.TopTools[event.Metadata.ToolName]++
+			}
+		case BehaviorSkillExec:
+			if event.Metadata.SkillName != "" {
+				stats.TopSkills[event.Metadata.SkillName]++
+			}
 		case BehaviorError:
 			stats.TotalErrors++
 		}
@@ -103,10 +148,17 @@ func (a *Analyzer) AnalyzePeriod(startDate, endDate time.Time) (*Statistics, err
 		stats.DailyActivity[day]++
 	}
 
+	if eventCount == 0 {
+		return &Statistics{Period: stats.Period, RedactionActive: stats.RedactionActive}, nil
+	}
+
 	// Calculate averages
 	if responseCount > 0 {
 		stats.AvgResponseTime = totalResponseTime / time.Duration(responseCount)
 	}
+	if firstTokenCount > 0 {
+		stats.AvgTimeToFirstToken = totalTimeToFirstToken / time.Duration(firstTokenCount)
+	}
 	if sessionCount > 0 {
 		stats.AvgSessionTime = totalSessionTime / time.Duration(sessionCount)
 	}
@@ -126,8 +178,10 @@ func (a *Analyzer) AnalyzePeriod(startDate, endDate time.Time) (*Statistics, err
 		}
 	}
 
-	// Calculate productivity score (simple heuristic)
-	stats.ProductivityScore = a.calculateProductivityScore(stats)
+	// Score against the rolling EWMA baseline: this both flags anomalies
+	// and derives the productivity score, then folds this period's values
+	// into the persisted baseline for next time.
+	stats.Anomalies, stats.ProductivityScore = a.scoreAgainstBaseline(stats, startDate, endDate)
 
 	return stats, nil
 }
@@ -154,64 +208,133 @@ func (a *Analyzer) AnalyzeMonth() (*Statistics, error) {
 	return a.AnalyzePeriod(startDate, endDate)
 }
 
-// loadEventsByPeriod loads all events within a date range
-func (a *Analyzer) loadEventsByPeriod(startDate, endDate time.Time) ([]BehaviorEvent, error) {
-	var allEvents []BehaviorEvent
-
-	// Iterate through each day in the period
-	currentDate := startDate
-	for currentDate.Before(endDate) || currentDate.Equal(endDate) {
-		dateStr := currentDate.Format("2006-01-02")
-		filePath := filepath.Join(a.dataPath, fmt.Sprintf("events-%s.json", dateStr))
-
-		// Read events from file
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				// File doesn't exist for this day, skip
-				currentDate = currentDate.AddDate(0, 0, 1)
-				continue
-			}
-			return nil, fmt.Errorf("failed to read events file %s: %w", filePath, err)
-		}
+// metricValues extracts the per-day/per-event figures tracked against a
+// rolling baseline from stats, given the period's length in days.
+func metricValues(stats *Statistics, days float64) map[string]float64 {
+	return map[string]float64{
+		"queries_per_day":            float64(stats.TotalQueries) / days,
+		"errors_per_day":             float64(stats.TotalErrors) / days,
+		"avg_response_time_seconds":  stats.AvgResponseTime.Seconds(),
+		"avg_session_length_seconds": stats.AvgSessionTime.Seconds(),
+	}
+}
 
-		var dayEvents []BehaviorEvent
-		if err := json.Unmarshal(data, &dayEvents); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal events from %s: %w", filePath, err)
-		}
+// productivityWeight returns how much a one-sigma deviation in metric
+// should move the productivity score, and in which direction: queries
+// running above baseline is good (engagement), errors and response time
+// running above baseline is bad.
+func productivityWeight(metric string) float64 {
+	switch metric {
+	case "queries_per_day":
+		return 10
+	case "errors_per_day":
+		return -10
+	case "avg_response_time_seconds":
+		return -5
+	default:
+		return 0
+	}
+}
 
-		// Filter events by time range
-		for _, event := range dayEvents {
-			if (event.Timestamp.After(startDate) || event.Timestamp.Equal(startDate)) &&
-				event.Timestamp.Before(endDate) {
-				allEvents = append(allEvents, event)
-			}
-		}
+// clampSigma bounds z to [-3, 3] so a single wild outlier can't swing the
+// productivity score outside its 0-100 range on its own.
+func clampSigma(z float64) float64 {
+	switch {
+	case z > anomalyAlertSigma:
+		return anomalyAlertSigma
+	case z < -anomalyAlertSigma:
+		return -anomalyAlertSigma
+	default:
+		return z
+	}
+}
 
-		currentDate = currentDate.AddDate(0, 0, 1)
+// periodBucket classifies a date range's length into the coarse timescale
+// its baselines are kept at, so AnalyzeToday/Week/Month don't fold their
+// very different typical magnitudes (e.g. errors_per_day) into the same
+// baseline entry.
+func periodBucket(days float64) string {
+	switch {
+	case days <= 1:
+		return "daily"
+	case days <= 10:
+		return "weekly"
+	default:
+		return "monthly"
 	}
+}
 
-	return allEvents, nil
+// updateCadence is the minimum real time that must pass between baseline
+// updates for a bucket, so a dashboard calling AnalyzeToday repeatedly
+// within the same day scores against the baseline without folding the
+// same period into it over and over.
+func updateCadence(bucket string) time.Duration {
+	switch bucket {
+	case "daily":
+		return 24 * time.Hour
+	case "weekly":
+		return 7 * 24 * time.Hour
+	default:
+		return 30 * 24 * time.Hour
+	}
 }
 
-// calculateProductivityScore calculates a productivity score based on various metrics
-func (a *Analyzer) calculateProductivityScore(stats *Statistics) float64 {
-	score := 0.0
+// scoreAgainstBaseline compares stats's per-day/per-event metrics to a's
+// persisted EWMA baselines (namespaced by periodBucket, so AnalyzeToday,
+// AnalyzeWeek, and AnalyzeMonth each score against their own baseline
+// entries instead of a shared one), returning any deviations of at least
+// anomalyNoteworthySigma and a productivity score derived from how far
+// those metrics sit from their rolling baseline. A baseline entry is only
+// folded forward once per updateCadence, so repeated reads of the same
+// period (e.g. a dashboard polling AnalyzeToday) score idempotently
+// instead of drifting the baseline toward whatever was last observed.
+func (a *Analyzer) scoreAgainstBaseline(stats *Statistics, startDate, endDate time.Time) ([]Anomaly, float64) {
+	days := endDate.Sub(startDate).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+	bucket := periodBucket(days)
+	cadence := updateCadence(bucket)
 
-	// Higher score for more queries (engagement)
-	if stats.TotalQueries > 0 {
-		score += float64(stats.TotalQueries) * 0.5
+	set, err := loadBaselineSet(a.baselinePath)
+	if err != nil {
+		// A corrupt or unreadable baseline file shouldn't block reporting
+		// statistics; start fresh instead.
+		set = make(baselineSet)
 	}
 
-	// Lower score for errors
-	score -= float64(stats.TotalErrors) * 2.0
+	var anomalies []Anomaly
+	score := 50.0
+	now := time.Now()
+	dirty := false
+	for metric, x := range metricValues(stats, days) {
+		key := metric + ":" + bucket
+
+		z := 0.0
+		if b, ok := set[key]; ok {
+			z = b.zScore(x)
+			if math.Abs(z) >= anomalyNoteworthySigma {
+				anomalies = append(anomalies, Anomaly{Metric: metric, Observed: x, Expected: b.Mean, Sigma: z})
+			}
+		}
+
+		score += productivityWeight(metric) * clampSigma(z)
+
+		if b, ok := set[key]; !ok || now.Sub(b.LastUpdated) >= cadence {
+			set.updateMetric(key, x)
+			set[key].LastUpdated = now
+			dirty = true
+		}
+	}
 
-	// Bonus for consistent activity
-	if len(stats.DailyActivity) > 1 {
-		score += float64(len(stats.DailyActivity)) * 2.0
+	if dirty && a.baselinePath != "" {
+		if err := set.save(a.baselinePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to persist baselines: %v\n", err)
+		}
 	}
 
-	// Normalize to 0-100 range
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].Metric < anomalies[j].Metric })
+
 	if score < 0 {
 		score = 0
 	}
@@ -219,7 +342,60 @@ func (a *Analyzer) calculateProductivityScore(stats *Statistics) float64 {
 		score = 100
 	}
 
-	return score
+	return anomalies, score
+}
+
+// DetectAnomalies analyzes period ("today", "week", or "month") and
+// returns any metrics whose value deviated meaningfully from their rolling
+// baseline.
+func (a *Analyzer) DetectAnomalies(period string) ([]Anomaly, error) {
+	var stats *Statistics
+	var err error
+
+	switch period {
+	case "today":
+		stats, err = a.AnalyzeToday()
+	case "week":
+		stats, err = a.AnalyzeWeek()
+	case "month":
+		stats, err = a.AnalyzeMonth()
+	default:
+		return nil, fmt.Errorf("invalid period: %s (must be today, week, or month)", period)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stats.Anomalies, nil
+}
+
+// ResetBaselines deletes the persisted EWMA baselines, so the next
+// AnalyzePeriod call starts re-learning each metric from scratch.
+func (a *Analyzer) ResetBaselines() error {
+	if a.baselinePath == "" {
+		return nil
+	}
+	if err := os.Remove(a.baselinePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to reset baselines: %w", err)
+	}
+	return nil
+}
+
+// metricLabel returns a human-readable description of a baseline metric
+// name, for use in anomaly insight text.
+func metricLabel(metric string) string {
+	switch metric {
+	case "queries_per_day":
+		return "Query volume"
+	case "errors_per_day":
+		return "Error rate"
+	case "avg_response_time_seconds":
+		return "Average response time"
+	case "avg_session_length_seconds":
+		return "Average session length"
+	default:
+		return metric
+	}
 }
 
 // GetInsights generates insights from statistics
@@ -257,6 +433,23 @@ func GetInsights(stats *Statistics) []string {
 		insights = append(insights, fmt.Sprintf("Productivity could be improved. Score: %.1f/100", stats.ProductivityScore))
 	}
 
+	// Anomaly insights: how far each flagged metric sits from its rolling
+	// baseline, e.g. "Error rate is 3.2σ above your rolling baseline".
+	for _, anomaly := range stats.Anomalies {
+		direction := "above"
+		if anomaly.Sigma < 0 {
+			direction = "below"
+		}
+
+		severity := "Warning"
+		if math.Abs(anomaly.Sigma) >= anomalyAlertSigma {
+			severity = "Alert"
+		}
+
+		insights = append(insights, fmt.Sprintf("%s: %s is %.1fσ %s your rolling baseline (expected ~%.2f, observed %.2f)",
+			severity, metricLabel(anomaly.Metric), math.Abs(anomaly.Sigma), direction, anomaly.Expected, anomaly.Observed))
+	}
+
 	// Top commands insight
 	if len(stats.TopCommands) > 0 {
 		topCmd := ""
@@ -278,6 +471,9 @@ func FormatStatistics(stats *Statistics) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("=== Statistics for %s ===\n\n", stats.Period))
+	if stats.RedactionActive {
+		sb.WriteString("(content truncation/redaction is active for tracked queries and responses)\n\n")
+	}
 	sb.WriteString(fmt.Sprintf("Total Queries:      %d\n", stats.TotalQueries))
 	sb.WriteString(fmt.Sprintf("Total Responses:    %d\n", stats.TotalResponses))
 	sb.WriteString(fmt.Sprintf("Total Commands:     %d\n", stats.TotalCommands))
@@ -288,11 +484,17 @@ func FormatStatistics(stats *Statistics) string {
 	if stats.AvgResponseTime > 0 {
 		sb.WriteString(fmt.Sprintf("Avg Response Time:  %v\n", stats.AvgResponseTime.Round(time.Millisecond)))
 	}
+	if stats.AvgTimeToFirstToken > 0 {
+		sb.WriteString(fmt.Sprintf("Avg Time to First Token: %v\n", stats.AvgTimeToFirstToken.Round(time.Millisecond)))
+	}
 	if stats.AvgSessionTime > 0 {
 		sb.WriteString(fmt.Sprintf("Avg Session Time:   %v\n", stats.AvgSessionTime.Round(time.Minute)))
 	}
 
 	sb.WriteString(fmt.Sprintf("Total Tokens:       %d\n", stats.TotalTokens))
+	if stats.EstimatedCostUSD > 0 {
+		sb.WriteString(fmt.Sprintf("Estimated Cost:     $%.4f\n", stats.EstimatedCostUSD))
+	}
 	sb.WriteString(fmt.Sprintf("Most Active Hour:   %d:00\n", stats.MostActiveHour))
 	sb.WriteString(fmt.Sprintf("Productivity Score: %.1f/100\n", stats.ProductivityScore))
 
@@ -321,6 +523,56 @@ func FormatStatistics(stats *Statistics) string {
 		}
 	}
 
+	// Top tools
+	if len(stats.TopTools) > 0 {
+		sb.WriteString("\nTop Tools:\n")
+
+		// Sort tools by count
+		type toolCount struct {
+			tool  string
+			count int
+		}
+		tools := make([]toolCount, 0, len(stats.TopTools))
+		for tool, count := range stats.TopTools {
+			tools = append(tools, toolCount{tool, count})
+		}
+		sort.Slice(tools, func(i, j int) bool {
+			return tools[i].count > tools[j].count
+		})
+
+		for i, tc := range tools {
+			if i >= 5 {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("  %d. %s: %d times\n", i+1, tc.tool, tc.count))
+		}
+	}
+
+	// Top skills
+	if len(stats.TopSkills) > 0 {
+		sb.WriteString("\nTop Skills:\n")
+
+		// Sort skills by count
+		type skillCount struct {
+			skill string
+			count int
+		}
+		skills := make([]skillCount, 0, len(stats.TopSkills))
+		for skill, count := range stats.TopSkills {
+			skills = append(skills, skillCount{skill, count})
+		}
+		sort.Slice(skills, func(i, j int) bool {
+			return skills[i].count > skills[j].count
+		})
+
+		for i, sc := range skills {
+			if i >= 5 {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("  %d. %s: %d times\n", i+1, sc.skill, sc.count))
+		}
+	}
+
 	// Daily activity
 	if len(stats.DailyActivity) > 0 {
 		sb.WriteString("\nDaily Activity:\n")
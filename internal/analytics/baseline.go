@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// baselineAlpha is the EWMA smoothing factor used to roll each metric's
+// baseline forward: mean_new = α*x + (1-α)*mean_old.
+const baselineAlpha = 0.1
+
+// minBaselineVariance floors a metric's baseline variance when scoring a
+// new observation. A metric that has never deviated from its mean (e.g.
+// errors_per_day sitting at 0 for every period so far) has a true EWMA
+// variance of exactly 0, which would make zScore divide by zero and hide
+// every future deviation, however large, behind the "not enough variance"
+// guard. Flooring the variance falls back to treating any deviation from
+// an otherwise-perfect baseline as meaningful, proportional to its size.
+const minBaselineVariance = 1.0
+
+// Sigma thresholds for Anomaly significance, per warm3snow/WorkWise#chunk1-5.
+const (
+	anomalyNoteworthySigma = 2.0
+	anomalyAlertSigma      = 3.0
+)
+
+// MetricBaseline is the rolling EWMA mean/variance for a single tracked
+// metric, used to score new observations as a z-score deviation.
+type MetricBaseline struct {
+	Mean        float64   `json:"mean"`
+	Variance    float64   `json:"variance"`
+	Samples     int       `json:"samples"`
+	LastUpdated time.Time `json:"last_updated,omitempty"`
+}
+
+// update folds x into the baseline:
+//
+//	mean_new = α*x + (1-α)*mean_old
+//	var_new  = α*(x-mean_old)² + (1-α)*var_old
+//
+// The first sample seeds the mean with zero variance, since a single point
+// carries no spread information.
+func (b *MetricBaseline) update(x float64) {
+	if b.Samples == 0 {
+		b.Mean = x
+		b.Variance = 0
+		b.Samples = 1
+		return
+	}
+
+	meanOld := b.Mean
+	b.Mean = baselineAlpha*x + (1-baselineAlpha)*meanOld
+	b.Variance = baselineAlpha*(x-meanOld)*(x-meanOld) + (1-baselineAlpha)*b.Variance
+	b.Samples++
+}
+
+// zScore reports how many standard deviations x is from the baseline mean.
+// It returns 0 until the baseline has accumulated at least two samples;
+// the variance is floored at minBaselineVariance so a baseline with no
+// observed spread yet can still flag a large deviation.
+func (b *MetricBaseline) zScore(x float64) float64 {
+	if b.Samples < 2 {
+		return 0
+	}
+	variance := b.Variance
+	if variance < minBaselineVariance {
+		variance = minBaselineVariance
+	}
+	return (x - b.Mean) / math.Sqrt(variance)
+}
+
+// Anomaly describes a single metric's deviation from its rolling baseline.
+type Anomaly struct {
+	Metric   string  `json:"metric"`
+	Observed float64 `json:"observed"`
+	Expected float64 `json:"expected"`
+	Sigma    float64 `json:"sigma"`
+}
+
+// baselineSet is the persisted collection of MetricBaselines, keyed by
+// metric name and period bucket (e.g. "errors_per_day:daily"), stored as
+// <dataPath>/baselines.json.
+type baselineSet map[string]*MetricBaseline
+
+// loadBaselineSet reads the baseline file at path, returning an empty set
+// if it doesn't exist yet.
+func loadBaselineSet(path string) (baselineSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(baselineSet), nil
+		}
+		return nil, fmt.Errorf("failed to read baselines: %w", err)
+	}
+
+	set := make(baselineSet)
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal baselines: %w", err)
+	}
+	return set, nil
+}
+
+// save writes set to path as indented JSON.
+func (set baselineSet) save(path string) error {
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baselines: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write baselines: %w", err)
+	}
+	return nil
+}
+
+// updateMetric folds x into set's baseline for metric, creating it if this
+// is the first observation.
+func (set baselineSet) updateMetric(metric string, x float64) {
+	b, ok := set[metric]
+	if !ok {
+		b = &MetricBaseline{}
+		set[metric] = b
+	}
+	b.update(x)
+}
@@ -0,0 +1,186 @@
+package analytics
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	eventsBucket       = []byte("events")
+	typeIndexBucket    = []byte("idx_type")
+	sessionIndexBucket = []byte("idx_session")
+)
+
+// BoltStore persists BehaviorEvents in a single BoltDB file, keyed by
+// Timestamp so Stream can seek directly to a time range instead of
+// scanning every shard the way JSONStore does. Secondary indexes on Type
+// and SessionID are maintained alongside the primary bucket so future
+// callers can filter by either without a full scan.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB file at path and
+// initializes its buckets.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt store at %q: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{eventsBucket, typeIndexBucket, sessionIndexBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize bolt store buckets: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// eventKey orders events chronologically within the events bucket (and
+// appends the event ID to disambiguate events recorded in the same
+// nanosecond), so Stream and Prune can seek instead of scanning.
+func eventKey(event BehaviorEvent) []byte {
+	key := make([]byte, 8+len(event.ID))
+	binary.BigEndian.PutUint64(key, uint64(event.Timestamp.UnixNano()))
+	copy(key[8:], event.ID)
+	return key
+}
+
+// indexKey builds a secondary-index key: the indexed value, a NUL
+// separator, then the primary event key, so a prefix scan over indexValue
+// yields matching events in primary-key (chronological) order.
+func indexKey(indexValue string, primaryKey []byte) []byte {
+	key := append([]byte(indexValue), 0x00)
+	return append(key, primaryKey...)
+}
+
+// Append persists event and updates its Type/SessionID index entries.
+func (s *BoltStore) Append(event BehaviorEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	key := eventKey(event)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(eventsBucket).Put(key, data); err != nil {
+			return err
+		}
+		if err := tx.Bucket(typeIndexBucket).Put(indexKey(string(event.Type), key), nil); err != nil {
+			return err
+		}
+		if event.Metadata.SessionID != "" {
+			if err := tx.Bucket(sessionIndexBucket).Put(indexKey(event.Metadata.SessionID, key), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stream seeks to start and emits events in chronological order until it
+// passes end, without ever loading the full range into memory at once.
+func (s *BoltStore) Stream(start, end time.Time) (<-chan BehaviorEvent, <-chan error) {
+	events := make(chan BehaviorEvent)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		startKey := make([]byte, 8)
+		binary.BigEndian.PutUint64(startKey, uint64(start.UnixNano()))
+		endNano := uint64(end.UnixNano())
+
+		err := s.db.View(func(tx *bolt.Tx) error {
+			c := tx.Bucket(eventsBucket).Cursor()
+			for k, v := c.Seek(startKey); k != nil; k, v = c.Next() {
+				if binary.BigEndian.Uint64(k[:8]) > endNano {
+					break
+				}
+
+				var event BehaviorEvent
+				if err := json.Unmarshal(v, &event); err != nil {
+					return fmt.Errorf("failed to unmarshal event: %w", err)
+				}
+				events <- event
+			}
+			return nil
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return events, errs
+}
+
+// Between implements Store.Between via storeBetween.
+func (s *BoltStore) Between(start, end time.Time) iter.Seq[BehaviorEvent] {
+	return storeBetween(s, start, end)
+}
+
+// Prune deletes every event with a Timestamp before cutoff, along with
+// its Type/SessionID index entries.
+func (s *BoltStore) Prune(cutoff time.Time) error {
+	cutoffNano := uint64(cutoff.UnixNano())
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		eventsB := tx.Bucket(eventsBucket)
+		typeB := tx.Bucket(typeIndexBucket)
+		sessionB := tx.Bucket(sessionIndexBucket)
+
+		type stale struct {
+			key   []byte
+			event BehaviorEvent
+		}
+		var toDelete []stale
+
+		c := eventsB.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if binary.BigEndian.Uint64(k[:8]) >= cutoffNano {
+				break
+			}
+
+			var event BehaviorEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return fmt.Errorf("failed to unmarshal event during prune: %w", err)
+			}
+			toDelete = append(toDelete, stale{key: append([]byte(nil), k...), event: event})
+		}
+
+		for _, s := range toDelete {
+			if err := eventsB.Delete(s.key); err != nil {
+				return err
+			}
+			if err := typeB.Delete(indexKey(string(s.event.Type), s.key)); err != nil {
+				return err
+			}
+			if s.event.Metadata.SessionID != "" {
+				if err := sessionB.Delete(indexKey(s.event.Metadata.SessionID, s.key)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close closes the underlying BoltDB file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
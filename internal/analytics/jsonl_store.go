@@ -0,0 +1,522 @@
+package analytics
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for JSONLStoreConfig's zero-valued fields.
+const (
+	defaultBatchSize     = 50
+	defaultBatchInterval = 2 * time.Second
+	defaultRotateBytes   = 10 * 1024 * 1024 // 10 MiB
+	defaultQueueSize     = 256
+)
+
+// JSONLStoreConfig configures a JSONLStore's async ingestion and rotation
+// behavior. Zero-valued fields fall back to the package defaults.
+type JSONLStoreConfig struct {
+	// DataPath is the directory events-YYYY-MM-DD.jsonl shards are written
+	// to and read from.
+	DataPath string
+
+	// BatchSize flushes the writer's pending batch to disk once it holds
+	// this many events.
+	BatchSize int
+
+	// BatchInterval flushes the writer's pending batch at least this
+	// often, regardless of BatchSize, so a slow trickle of events doesn't
+	// sit unflushed indefinitely.
+	BatchInterval time.Duration
+
+	// RotateBytes gzip-rotates a shard once its live (uncompressed) file
+	// exceeds this many bytes.
+	RotateBytes int64
+
+	// QueueSize is the capacity of Append's buffered channel.
+	QueueSize int
+}
+
+// withDefaults returns cfg with every zero-valued field replaced by its
+// package default.
+func (cfg JSONLStoreConfig) withDefaults() JSONLStoreConfig {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.BatchInterval <= 0 {
+		cfg.BatchInterval = defaultBatchInterval
+	}
+	if cfg.RotateBytes <= 0 {
+		cfg.RotateBytes = defaultRotateBytes
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	return cfg
+}
+
+// JSONLStore is the default Store backend: an append-only
+// events-YYYY-MM-DD.jsonl file per day, fed by a buffered channel and a
+// single writer goroutine so Append never re-reads or re-marshals a
+// shard's existing contents the way JSONStore.Append does. A shard is
+// gzip-rotated into events-YYYY-MM-DD.N.jsonl.gz once it exceeds
+// RotateBytes, and Prune removes shards (live or rotated) dated entirely
+// before a cutoff.
+type JSONLStore struct {
+	cfg JSONLStoreConfig
+
+	queue     chan BehaviorEvent
+	flushReq  chan chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewJSONLStore creates a JSONLStore per cfg. The writer goroutine starts
+// immediately; DataPath is created lazily on the first write, not here, so
+// constructing a read-only JSONLStore (e.g. for Analyzer) never touches
+// the filesystem until something is actually appended.
+func NewJSONLStore(cfg JSONLStoreConfig) *JSONLStore {
+	cfg = cfg.withDefaults()
+
+	s := &JSONLStore{
+		cfg:      cfg,
+		queue:    make(chan BehaviorEvent, cfg.QueueSize),
+		flushReq: make(chan chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.writeLoop()
+
+	return s
+}
+
+// Append queues event for the writer goroutine. It returns immediately
+// unless the queue is full, in which case it blocks until space frees up
+// or the store is closed.
+func (s *JSONLStore) Append(event BehaviorEvent) error {
+	select {
+	case s.queue <- event:
+		return nil
+	case <-s.stopped:
+		return fmt.Errorf("jsonl store is closed")
+	}
+}
+
+// Flush blocks until the writer goroutine has processed every event queued
+// so far. A batch that fails to write is logged to stderr by the writer and
+// is not reported here; Flush returns an error only if the store is closed.
+func (s *JSONLStore) Flush() error {
+	ack := make(chan struct{})
+	select {
+	case s.flushReq <- ack:
+		<-ack
+		return nil
+	case <-s.stopped:
+		return fmt.Errorf("jsonl store is closed")
+	}
+}
+
+// Close stops the writer goroutine after flushing whatever is still
+// queued.
+func (s *JSONLStore) Close() error {
+	s.closeOnce.Do(func() { close(s.stopped) })
+	s.wg.Wait()
+	return nil
+}
+
+// writeLoop is the sole writer of shard files: it batches queued events by
+// BatchSize/BatchInterval, then hands each batch to writeBatch.
+func (s *JSONLStore) writeLoop() {
+	defer s.wg.Done()
+
+	batch := make([]BehaviorEvent, 0, s.cfg.BatchSize)
+	ticker := time.NewTicker(s.cfg.BatchInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.writeBatch(batch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write analytics batch: %v\n", err)
+		}
+		batch = batch[:0]
+	}
+
+	drainQueue := func() {
+		for {
+			select {
+			case event := <-s.queue:
+				batch = append(batch, event)
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case event := <-s.queue:
+			batch = append(batch, event)
+			if len(batch) >= s.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case ack := <-s.flushReq:
+			drainQueue()
+			flush()
+			close(ack)
+		case <-s.stopped:
+			drainQueue()
+			flush()
+			return
+		}
+	}
+}
+
+// writeBatch groups batch by the date of each event's Timestamp and
+// appends each group to its shard in one open/write/close cycle.
+func (s *JSONLStore) writeBatch(batch []BehaviorEvent) error {
+	byDate := make(map[string][]BehaviorEvent)
+	for _, event := range batch {
+		date := event.Timestamp.Format("2006-01-02")
+		byDate[date] = append(byDate[date], event)
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		if err := s.appendShard(date, byDate[date]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendShard appends events to date's live shard, creating DataPath if
+// necessary, then rotates the shard if it now exceeds RotateBytes.
+func (s *JSONLStore) appendShard(date string, events []BehaviorEvent) error {
+	if err := os.MkdirAll(s.cfg.DataPath, 0755); err != nil {
+		return fmt.Errorf("failed to create analytics data directory: %w", err)
+	}
+
+	path := s.livePath(date)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open shard %q: %w", path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event %s: %w", event.ID, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write event %s to shard %q: %w", event.ID, path, err)
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed to write event %s to shard %q: %w", event.ID, path, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush shard %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close shard %q: %w", path, err)
+	}
+
+	return s.rotateIfNeeded(path, date)
+}
+
+// livePath returns the path of date's live (uncompressed, currently-being-
+// written) shard.
+func (s *JSONLStore) livePath(date string) string {
+	return filepath.Join(s.cfg.DataPath, fmt.Sprintf("events-%s.jsonl", date))
+}
+
+// rotateIfNeeded gzip-compresses path into the next free
+// events-DATE.N.jsonl.gz rotation and removes it, if path's size now
+// exceeds RotateBytes.
+func (s *JSONLStore) rotateIfNeeded(path, date string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat shard %q: %w", path, err)
+	}
+	if info.Size() <= s.cfg.RotateBytes {
+		return nil
+	}
+
+	idx := 1
+	var target string
+	for {
+		target = filepath.Join(s.cfg.DataPath, fmt.Sprintf("events-%s.%d.jsonl.gz", date, idx))
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			break
+		}
+		idx++
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read shard %q for rotation: %w", path, err)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create rotated shard %q: %w", target, err)
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := gz.Write(data); err != nil {
+		gz.Close()
+		out.Close()
+		return fmt.Errorf("failed to compress rotated shard %q: %w", target, err)
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to finalize rotated shard %q: %w", target, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close rotated shard %q: %w", target, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove live shard %q after rotation: %w", path, err)
+	}
+	return nil
+}
+
+// Stream emits, in chronological order, every event with a Timestamp in
+// [start, end], reading each day's rotated shards (oldest rotation first)
+// followed by its live shard.
+func (s *JSONLStore) Stream(start, end time.Time) (<-chan BehaviorEvent, <-chan error) {
+	events := make(chan BehaviorEvent)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		shards, err := s.shardsInRange(start, end)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for _, shard := range shards {
+			if err := s.emitShard(shard.path, start, end, events); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// Between implements Store.Between via storeBetween.
+func (s *JSONLStore) Between(start, end time.Time) iter.Seq[BehaviorEvent] {
+	return storeBetween(s, start, end)
+}
+
+// emitShard reads path (transparently gzip-decompressing a ".gz" shard)
+// line by line, sending every event whose Timestamp falls in [start, end].
+func (s *JSONLStore) emitShard(path string, start, end time.Time, out chan<- BehaviorEvent) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil // rotated or removed between listing and reading; skip
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open shard %q: %w", path, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to open gzip shard %q: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(strings.TrimSpace(line)) > 0 {
+			var event BehaviorEvent
+			if jsonErr := json.Unmarshal([]byte(line), &event); jsonErr != nil {
+				return fmt.Errorf("failed to unmarshal event from shard %q: %w", path, jsonErr)
+			}
+			if (event.Timestamp.After(start) || event.Timestamp.Equal(start)) && !event.Timestamp.After(end) {
+				out <- event
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read shard %q: %w", path, err)
+		}
+	}
+}
+
+// Prune deletes every shard (live or rotated) dated entirely before
+// cutoff.
+func (s *JSONLStore) Prune(cutoff time.Time) error {
+	shards, err := s.allShards()
+	if err != nil {
+		return err
+	}
+
+	cutoffDate := dateOnly(cutoff)
+	for _, shard := range shards {
+		if shard.date.Before(cutoffDate) {
+			if err := os.Remove(shard.path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to prune shard %q: %w", shard.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// jsonlShard is a single shard file (live or rotated) and the date/index
+// parsed from its name. index is 0 for the live shard and >=1 for a
+// rotated one, in rotation order.
+type jsonlShard struct {
+	path  string
+	date  time.Time
+	index int
+}
+
+// parseJSONLShardName parses path's basename into a jsonlShard, or reports
+// ok=false if it doesn't match the events-YYYY-MM-DD[.N].jsonl[.gz] layout.
+func parseJSONLShardName(path string) (shard jsonlShard, ok bool) {
+	name := strings.TrimPrefix(filepath.Base(path), "events-")
+
+	dateStr := name
+	index := 0
+	switch {
+	case strings.HasSuffix(name, ".jsonl.gz"):
+		name = strings.TrimSuffix(name, ".jsonl.gz")
+		parts := strings.SplitN(name, ".", 2)
+		dateStr = parts[0]
+		if len(parts) == 2 {
+			if n, err := strconv.Atoi(parts[1]); err == nil {
+				index = n
+			}
+		}
+	case strings.HasSuffix(name, ".jsonl"):
+		dateStr = strings.TrimSuffix(name, ".jsonl")
+	default:
+		return jsonlShard{}, false
+	}
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return jsonlShard{}, false
+	}
+	return jsonlShard{path: path, date: date, index: index}, true
+}
+
+// allShards lists every recognized shard file under DataPath.
+func (s *JSONLStore) allShards() ([]jsonlShard, error) {
+	matches, err := filepath.Glob(filepath.Join(s.cfg.DataPath, "events-*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics shards: %w", err)
+	}
+
+	shards := make([]jsonlShard, 0, len(matches))
+	for _, path := range matches {
+		if shard, ok := parseJSONLShardName(path); ok {
+			shards = append(shards, shard)
+		}
+	}
+	return shards, nil
+}
+
+// shardsInRange returns every shard covering a date in [start, end],
+// ordered chronologically: dates in ascending order, and within each date
+// its rotated shards by ascending index (oldest rotation first) followed
+// by its live shard.
+func (s *JSONLStore) shardsInRange(start, end time.Time) ([]jsonlShard, error) {
+	all, err := s.allShards()
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate := dateOnly(start), dateOnly(end)
+	inRange := all[:0]
+	for _, shard := range all {
+		if !shard.date.Before(startDate) && !shard.date.After(endDate) {
+			inRange = append(inRange, shard)
+		}
+	}
+
+	sort.Slice(inRange, func(i, j int) bool {
+		if !inRange[i].date.Equal(inRange[j].date) {
+			return inRange[i].date.Before(inRange[j].date)
+		}
+		// Within a date, a live shard (index 0) always holds the most
+		// recent events, so it must sort after every rotated shard; treat
+		// it as if it had the highest possible index.
+		li, lj := inRange[i].index, inRange[j].index
+		if li == 0 {
+			li = int(^uint(0) >> 1)
+		}
+		if lj == 0 {
+			lj = int(^uint(0) >> 1)
+		}
+		return li < lj
+	})
+
+	return inRange, nil
+}
+
+// ShardSize describes one on-disk analytics shard file and its size, for
+// reporting storage usage (e.g. `workwise stats`, via go-humanize).
+type ShardSize struct {
+	Name  string
+	Bytes int64
+}
+
+// Sizes lists every events-* shard file (JSONLStore's live/rotated
+// shards, or legacy JSONStore .json files) under dataPath with its size
+// on disk, sorted by name.
+func Sizes(dataPath string) ([]ShardSize, error) {
+	matches, err := filepath.Glob(filepath.Join(dataPath, "events-*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics shards: %w", err)
+	}
+
+	sizes := make([]ShardSize, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		sizes = append(sizes, ShardSize{Name: filepath.Base(path), Bytes: info.Size()})
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].Name < sizes[j].Name })
+	return sizes, nil
+}
@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/warm3snow/WorkWise/internal/config"
+)
+
+// truncationMarker is appended to content trimmed by MaxContentBytes.
+const truncationMarker = "...[truncated]"
+
+// redactionPattern pairs a built-in secret/PII shape with the label used in
+// its replacement marker.
+type redactionPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// redactionPatterns are the built-in regexes EventSanitizer applies when
+// RedactPatterns is enabled. Order matters: bearer tokens and AWS keys are
+// matched before the more general api-key pattern so they keep their more
+// specific label.
+var redactionPatterns = []redactionPattern{
+	{"email", regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)},
+	{"bearer-token", regexp.MustCompile(`(?i)\bBearer\s+[A-Za-z0-9\-._~+/]+=*`)},
+	{"aws-key", regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+	{"api-key", regexp.MustCompile(`\b(?:sk|pk|api)[-_][A-Za-z0-9]{16,}\b`)},
+	{"credit-card", regexp.MustCompile(`\b(?:\d[ -]?){13,16}\b`)},
+	{"ip-address", regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)},
+}
+
+// EventSanitizer truncates and/or redacts BehaviorEvent content before it's
+// persisted, per the configured AnalyticsPrivacyConfig. A nil *EventSanitizer
+// is a no-op, so callers can use one unconditionally.
+type EventSanitizer struct {
+	maxContentBytes int
+	redactPatterns  bool
+	hashOnly        bool
+}
+
+// NewEventSanitizer builds an EventSanitizer from cfg.
+func NewEventSanitizer(cfg config.AnalyticsPrivacyConfig) *EventSanitizer {
+	return &EventSanitizer{
+		maxContentBytes: cfg.MaxContentBytes,
+		redactPatterns:  cfg.RedactPatterns,
+		hashOnly:        cfg.HashOnly,
+	}
+}
+
+// Sanitize rewrites content according to the sanitizer's configuration.
+// Hash-only mode short-circuits everything else, since once content is
+// hashed there's no text left to redact or truncate; otherwise redaction
+// runs before truncation so a secret split across the truncation boundary
+// can't slip through.
+func (s *EventSanitizer) Sanitize(content string) string {
+	if s == nil || content == "" {
+		return content
+	}
+
+	if s.hashOnly {
+		sum := sha256.Sum256([]byte(content))
+		return fmt.Sprintf("sha256:%s (len=%d)", hex.EncodeToString(sum[:]), len(content))
+	}
+
+	if s.redactPatterns {
+		for _, p := range redactionPatterns {
+			content = p.re.ReplaceAllString(content, fmt.Sprintf("[redacted:%s]", p.name))
+		}
+	}
+
+	if s.maxContentBytes > 0 && len(content) > s.maxContentBytes {
+		content = content[:s.maxContentBytes] + truncationMarker
+	}
+
+	return content
+}
+
+// Active reports whether this sanitizer modifies content at all, so
+// FormatStatistics can indicate when redaction is in effect.
+func (s *EventSanitizer) Active() bool {
+	return s != nil && (s.hashOnly || s.redactPatterns || s.maxContentBytes > 0)
+}
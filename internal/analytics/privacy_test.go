@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/warm3snow/WorkWise/internal/config"
+)
+
+func TestEventSanitizerTruncates(t *testing.T) {
+	s := NewEventSanitizer(config.AnalyticsPrivacyConfig{MaxContentBytes: 10})
+
+	got := s.Sanitize("this is a much longer string than ten bytes")
+	if !strings.HasPrefix(got, "this is a ") {
+		t.Errorf("expected truncated content to keep the first 10 bytes, got %q", got)
+	}
+	if !strings.HasSuffix(got, truncationMarker) {
+		t.Errorf("expected truncated content to end with %q, got %q", truncationMarker, got)
+	}
+}
+
+func TestEventSanitizerRedactsPatterns(t *testing.T) {
+	s := NewEventSanitizer(config.AnalyticsPrivacyConfig{RedactPatterns: true})
+
+	got := s.Sanitize("contact me at jane@example.com or use Bearer abc123XYZtoken")
+	if strings.Contains(got, "jane@example.com") {
+		t.Errorf("expected email to be redacted, got %q", got)
+	}
+	if strings.Contains(got, "abc123XYZtoken") {
+		t.Errorf("expected bearer token to be redacted, got %q", got)
+	}
+	if !strings.Contains(got, "[redacted:email]") {
+		t.Errorf("expected email redaction marker, got %q", got)
+	}
+}
+
+func TestEventSanitizerHashOnly(t *testing.T) {
+	s := NewEventSanitizer(config.AnalyticsPrivacyConfig{HashOnly: true})
+
+	got := s.Sanitize("some secret content")
+	if strings.Contains(got, "secret") {
+		t.Errorf("expected hash-only content to contain no original text, got %q", got)
+	}
+	if !strings.HasPrefix(got, "sha256:") {
+		t.Errorf("expected hash-only content to be prefixed with sha256:, got %q", got)
+	}
+}
+
+func TestEventSanitizerNilIsNoop(t *testing.T) {
+	var s *EventSanitizer
+	if got := s.Sanitize("unchanged"); got != "unchanged" {
+		t.Errorf("expected nil sanitizer to pass content through unchanged, got %q", got)
+	}
+	if s.Active() {
+		t.Error("expected nil sanitizer to report Active() == false")
+	}
+}
+
+func TestEventSanitizerActive(t *testing.T) {
+	if (&EventSanitizer{}).Active() {
+		t.Error("expected a zero-value sanitizer to be inactive")
+	}
+	if !NewEventSanitizer(config.AnalyticsPrivacyConfig{MaxContentBytes: 4096}).Active() {
+		t.Error("expected a sanitizer with MaxContentBytes set to be active")
+	}
+}
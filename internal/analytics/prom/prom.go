@@ -0,0 +1,137 @@
+// Package prom implements an analytics.Sink that exposes the behavior
+// stream as Prometheus metrics, so operators can scrape WorkWise like any
+// other service instead of shelling scripts to parse events-YYYY-MM-DD.jsonl.
+package prom
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Exporter is a Prometheus-backed analytics.Sink. It satisfies that
+// interface structurally, so this package doesn't need to import
+// internal/analytics; callers wire it up with tracker.SetSink(exporter).
+type Exporter struct {
+	queries   prometheus.Counter
+	errors    prometheus.Counter
+	sessions  prometheus.Counter
+	cancelled prometheus.Counter
+
+	responses *prometheus.CounterVec
+	commands  *prometheus.CounterVec
+	tokens    *prometheus.CounterVec
+	toolCalls *prometheus.CounterVec
+
+	responseDuration *prometheus.HistogramVec
+
+	registry *prometheus.Registry
+}
+
+// NewExporter creates an Exporter with all collectors registered.
+func NewExporter() *Exporter {
+	registry := prometheus.NewRegistry()
+
+	e := &Exporter{
+		queries: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "workwise_queries_total",
+			Help: "Total number of user queries tracked.",
+		}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "workwise_errors_total",
+			Help: "Total number of errors tracked.",
+		}),
+		sessions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "workwise_sessions_total",
+			Help: "Total number of sessions tracked.",
+		}),
+		cancelled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "workwise_cancelled_total",
+			Help: "Total number of streamed responses cancelled mid-generation.",
+		}),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "workwise_responses_total",
+			Help: "Total number of AI responses tracked, by model.",
+		}, []string{"model"}),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "workwise_commands_total",
+			Help: "Total number of CLI commands tracked, by command name.",
+		}, []string{"command"}),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "workwise_tokens_total",
+			Help: "Total number of tokens (prompt + completion) used, by model.",
+		}, []string{"model"}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "workwise_tool_calls_total",
+			Help: "Total number of agent tool invocations, by tool name.",
+		}, []string{"tool"}),
+		responseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "workwise_response_duration_seconds",
+			Help:    "Response generation duration in seconds, by model.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"model"}),
+		registry: registry,
+	}
+
+	registry.MustRegister(
+		e.queries, e.errors, e.sessions, e.cancelled,
+		e.responses, e.commands, e.tokens, e.toolCalls,
+		e.responseDuration,
+	)
+
+	return e
+}
+
+// Handler returns the http.Handler serving this Exporter's collectors in
+// the Prometheus text exposition format.
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// RecordQuery implements analytics.Sink.
+func (e *Exporter) RecordQuery() {
+	e.queries.Inc()
+}
+
+// RecordResponse implements analytics.Sink.
+func (e *Exporter) RecordResponse(duration time.Duration, tokenCount int, model string, _ time.Time) {
+	if model == "" {
+		model = "unknown"
+	}
+	e.responses.WithLabelValues(model).Inc()
+	e.tokens.WithLabelValues(model).Add(float64(tokenCount))
+	e.responseDuration.WithLabelValues(model).Observe(duration.Seconds())
+}
+
+// RecordCommand implements analytics.Sink.
+func (e *Exporter) RecordCommand(name string, _ time.Time) {
+	if name == "" {
+		return
+	}
+	e.commands.WithLabelValues(name).Inc()
+}
+
+// RecordToolCall implements analytics.Sink.
+func (e *Exporter) RecordToolCall(name string, _ time.Time) {
+	if name == "" {
+		return
+	}
+	e.toolCalls.WithLabelValues(name).Inc()
+}
+
+// RecordError implements analytics.Sink.
+func (e *Exporter) RecordError() {
+	e.errors.Inc()
+}
+
+// RecordCancelled implements analytics.Sink.
+func (e *Exporter) RecordCancelled() {
+	e.cancelled.Inc()
+}
+
+// RecordSession implements analytics.Sink.
+func (e *Exporter) RecordSession() {
+	e.sessions.Inc()
+}
@@ -0,0 +1,31 @@
+package analytics
+
+import "time"
+
+// Sink receives a live update on every Tracker Track* call, letting
+// alternate exporters (e.g. the Prometheus one in analytics/prom) observe
+// the behavior stream in real time without displacing the JSON file writer,
+// which remains Tracker's source of truth for history and `workwise stats`.
+// Tracker.SetSink swaps it; a nil Sink (the default) is simply skipped.
+type Sink interface {
+	// RecordQuery is called on every Tracker.TrackQuery.
+	RecordQuery()
+
+	// RecordResponse is called on every Tracker.TrackResponse.
+	RecordResponse(duration time.Duration, tokenCount int, model string, timestamp time.Time)
+
+	// RecordCommand is called on every Tracker.TrackCommand.
+	RecordCommand(name string, timestamp time.Time)
+
+	// RecordToolCall is called on every Tracker.TrackToolCall.
+	RecordToolCall(name string, timestamp time.Time)
+
+	// RecordError is called on every Tracker.TrackError.
+	RecordError()
+
+	// RecordCancelled is called on every Tracker.TrackCancelled.
+	RecordCancelled()
+
+	// RecordSession is called on every Tracker.TrackSessionEnd.
+	RecordSession()
+}
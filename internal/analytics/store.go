@@ -0,0 +1,230 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Store is a pluggable backend for persisting and querying BehaviorEvents.
+// JSONStore is the original per-day events-YYYY-MM-DD.json layout;
+// JSONLStore is the default append-only, rotating/compressed layout
+// Tracker and Analyzer now use; BoltStore trades simplicity for an
+// indexed, bounded-memory alternative for larger histories.
+// Analyzer.AnalyzePeriod streams through whichever Store it's given rather
+// than loading a period's events into memory.
+type Store interface {
+	// Append persists a single event.
+	Append(event BehaviorEvent) error
+
+	// Stream emits, in chronological order, every event with a Timestamp
+	// in [start, end]. The events channel is closed once iteration
+	// completes or an error occurs; callers should check errs (buffered,
+	// capacity 1) after it closes.
+	Stream(start, end time.Time) (<-chan BehaviorEvent, <-chan error)
+
+	// Between is Stream adapted to Go's range-over-func iterators, for
+	// callers (e.g. Analyzer) that want a plain `for event := range ...`
+	// loop instead of managing a channel pair. Errors encountered while
+	// iterating are logged as warnings rather than surfaced, since
+	// iter.Seq has no channel for them; use Stream directly when strict
+	// error handling matters.
+	Between(start, end time.Time) iter.Seq[BehaviorEvent]
+
+	// Prune deletes every event with a Timestamp before cutoff.
+	Prune(cutoff time.Time) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// storeBetween implements Store.Between in terms of Stream, so each Store
+// implementation only has to provide Stream's channel pair.
+func storeBetween(s Store, start, end time.Time) iter.Seq[BehaviorEvent] {
+	return func(yield func(BehaviorEvent) bool) {
+		events, errs := s.Stream(start, end)
+		for event := range events {
+			if !yield(event) {
+				// Drain the rest so Stream's goroutine doesn't block
+				// forever trying to send to an abandoned iteration.
+				for range events {
+				}
+				return
+			}
+		}
+		if err := <-errs; err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to stream analytics events: %v\n", err)
+		}
+	}
+}
+
+// Migrate streams every event from src into dst and returns how many were
+// copied. Used by `workwise analytics migrate` to move the legacy
+// JSONStore's shards into an indexed BoltStore.
+func Migrate(src, dst Store) (int, error) {
+	events, errs := src.Stream(time.Time{}, time.Now())
+
+	count := 0
+	for event := range events {
+		if err := dst.Append(event); err != nil {
+			return count, fmt.Errorf("failed to append event %s to destination store: %w", event.ID, err)
+		}
+		count++
+	}
+	if err := <-errs; err != nil {
+		return count, fmt.Errorf("failed to stream source events: %w", err)
+	}
+
+	return count, nil
+}
+
+// JSONStore is the original per-day events-YYYY-MM-DD.json file layout.
+// Tracker no longer writes it (it writes through a JSONLStore); it remains
+// as the source for `workwise analytics migrate`.
+type JSONStore struct {
+	dataPath string
+}
+
+// NewJSONStore creates a JSONStore reading and writing shards under dataPath.
+func NewJSONStore(dataPath string) *JSONStore {
+	return &JSONStore{dataPath: dataPath}
+}
+
+// Append adds event to the shard for its Timestamp's date, re-reading and
+// rewriting that day's whole JSON array one event at a time.
+func (s *JSONStore) Append(event BehaviorEvent) error {
+	filePath := filepath.Join(s.dataPath, fmt.Sprintf("events-%s.json", event.Timestamp.Format("2006-01-02")))
+
+	var dayEvents []BehaviorEvent
+	if data, err := os.ReadFile(filePath); err == nil {
+		if err := json.Unmarshal(data, &dayEvents); err != nil {
+			return fmt.Errorf("failed to unmarshal existing events in %s: %w", filePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read events file %s: %w", filePath, err)
+	}
+
+	dayEvents = append(dayEvents, event)
+
+	data, err := json.MarshalIndent(dayEvents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal events: %w", err)
+	}
+	if err := os.MkdirAll(s.dataPath, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// Stream walks the shard files overlapping [start, end] in date order,
+// emitting only the events whose Timestamp actually falls in range.
+func (s *JSONStore) Stream(start, end time.Time) (<-chan BehaviorEvent, <-chan error) {
+	events := make(chan BehaviorEvent)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		shards, err := s.shardFiles()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		startDate, endDate := dateOnly(start), dateOnly(end)
+		for _, shard := range shards {
+			if shard.date.Before(startDate) || shard.date.After(endDate) {
+				continue
+			}
+
+			data, err := os.ReadFile(shard.path)
+			if err != nil {
+				errs <- fmt.Errorf("failed to read events file %s: %w", shard.path, err)
+				return
+			}
+
+			var dayEvents []BehaviorEvent
+			if err := json.Unmarshal(data, &dayEvents); err != nil {
+				errs <- fmt.Errorf("failed to unmarshal events from %s: %w", shard.path, err)
+				return
+			}
+
+			for _, event := range dayEvents {
+				if (event.Timestamp.After(start) || event.Timestamp.Equal(start)) && !event.Timestamp.After(end) {
+					events <- event
+				}
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// Between implements Store.Between via storeBetween.
+func (s *JSONStore) Between(start, end time.Time) iter.Seq[BehaviorEvent] {
+	return storeBetween(s, start, end)
+}
+
+// Prune removes every shard file dated entirely before cutoff.
+func (s *JSONStore) Prune(cutoff time.Time) error {
+	shards, err := s.shardFiles()
+	if err != nil {
+		return err
+	}
+
+	cutoffDate := dateOnly(cutoff)
+	for _, shard := range shards {
+		if shard.date.Before(cutoffDate) {
+			if err := os.Remove(shard.path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to prune shard %s: %w", shard.path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close is a no-op: JSONStore holds no open resources between calls.
+func (s *JSONStore) Close() error {
+	return nil
+}
+
+// jsonShard is a single events-YYYY-MM-DD.json file and the date parsed
+// from its name.
+type jsonShard struct {
+	path string
+	date time.Time
+}
+
+// shardFiles lists every recognized shard file in dataPath, sorted by date.
+func (s *JSONStore) shardFiles() ([]jsonShard, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dataPath, "events-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event shards: %w", err)
+	}
+
+	shards := make([]jsonShard, 0, len(matches))
+	for _, path := range matches {
+		dateStr := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "events-"), ".json")
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			continue // not a shard file this store recognizes, skip it
+		}
+		shards = append(shards, jsonShard{path: path, date: date})
+	}
+
+	sort.Slice(shards, func(i, j int) bool { return shards[i].date.Before(shards[j].date) })
+	return shards, nil
+}
+
+// dateOnly truncates t to midnight in its own location, for comparing
+// against shard file dates.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
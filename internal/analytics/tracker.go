@@ -1,23 +1,28 @@
 package analytics
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/warm3snow/WorkWise/internal/config"
 )
 
 // BehaviorType represents the type of user behavior
 type BehaviorType string
 
 const (
-	BehaviorQuery    BehaviorType = "query"
-	BehaviorResponse BehaviorType = "response"
-	BehaviorCommand  BehaviorType = "command"
-	BehaviorSession  BehaviorType = "session"
-	BehaviorError    BehaviorType = "error"
+	BehaviorQuery     BehaviorType = "query"
+	BehaviorResponse  BehaviorType = "response"
+	BehaviorCommand   BehaviorType = "command"
+	BehaviorSession   BehaviorType = "session"
+	BehaviorError     BehaviorType = "error"
+	BehaviorCancelled BehaviorType = "cancelled"
+	BehaviorToolCall  BehaviorType = "tool_call"
+	BehaviorSkillExec BehaviorType = "skill_exec"
 )
 
 // BehaviorEvent represents a single user behavior event
@@ -31,13 +36,27 @@ type BehaviorEvent struct {
 
 // Metadata contains additional information about the behavior
 type Metadata struct {
-	Duration     time.Duration `json:"duration,omitempty"`
-	TokenCount   int           `json:"token_count,omitempty"`
-	Model        string        `json:"model,omitempty"`
-	Success      bool          `json:"success"`
-	ErrorMessage string        `json:"error_message,omitempty"`
-	SessionID    string        `json:"session_id,omitempty"`
-	CommandName  string        `json:"command_name,omitempty"`
+	Duration           time.Duration `json:"duration,omitempty"`
+	TimeToFirstTokenMs int64         `json:"time_to_first_token_ms,omitempty"`
+	TokenCount         int           `json:"token_count,omitempty"`
+	PromptTokens       int           `json:"prompt_tokens,omitempty"`
+	CompletionTokens   int           `json:"completion_tokens,omitempty"`
+	EstimatedCostUSD   float64       `json:"estimated_cost_usd,omitempty"`
+	Model              string        `json:"model,omitempty"`
+	Success            bool          `json:"success"`
+	ErrorMessage       string        `json:"error_message,omitempty"`
+	SessionID          string        `json:"session_id,omitempty"`
+	CommandName        string        `json:"command_name,omitempty"`
+	ToolName           string        `json:"tool_name,omitempty"`
+	SkillName          string        `json:"skill_name,omitempty"`
+}
+
+// Usage carries the token accounting for a single LLM call. It mirrors
+// llm.Usage without importing that package, since analytics only cares
+// about the numbers, not how a given provider produced them.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
 }
 
 // Tracker tracks user behaviors
@@ -47,10 +66,17 @@ type Tracker struct {
 	events       []BehaviorEvent
 	sessionID    string
 	sessionStart time.Time
+	pricing      map[string]config.ModelPricing
+	sink         Sink
+	sanitizer    *EventSanitizer
+	store        *JSONLStore
 	mu           sync.RWMutex
 }
 
-// NewTracker creates a new behavior tracker
+// NewTracker creates a new behavior tracker, backed by a JSONLStore
+// writing events-YYYY-MM-DD.jsonl shards under dataPath. Unlike the
+// previous per-Track-call full-file rewrite, every addEvent flush is an
+// O(1) append handled by the store's own writer goroutine.
 func NewTracker(enabled bool, dataPath string) (*Tracker, error) {
 	if !enabled {
 		return &Tracker{enabled: false}, nil
@@ -69,15 +95,42 @@ func NewTracker(enabled bool, dataPath string) (*Tracker, error) {
 		events:       make([]BehaviorEvent, 0),
 		sessionID:    sessionID,
 		sessionStart: time.Now(),
+		store:        NewJSONLStore(JSONLStoreConfig{DataPath: dataPath}),
 	}
 
-	// Load existing events from today's file
-	if err := tracker.loadTodayEvents(); err != nil {
-		// Log error but don't fail - start with empty events
-		fmt.Fprintf(os.Stderr, "Warning: failed to load existing events: %v\n", err)
+	return tracker, nil
+}
+
+// Close flushes any buffered events and stops the underlying store's
+// writer goroutine. Safe to call on a disabled tracker.
+func (t *Tracker) Close() error {
+	if !t.enabled {
+		return nil
 	}
+	if err := t.persist(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to persist events on close: %v\n", err)
+	}
+	return t.store.Close()
+}
 
-	return tracker, nil
+// SetPricing configures the per-model pricing table used to compute
+// EstimatedCostUSD on subsequent TrackResponse calls.
+func (t *Tracker) SetPricing(pricing map[string]config.ModelPricing) {
+	t.pricing = pricing
+}
+
+// SetSink attaches a Sink (e.g. the Prometheus Exporter in analytics/prom)
+// that receives a live update on every subsequent Track* call, alongside
+// the JSON file writer. Pass nil to detach it.
+func (t *Tracker) SetSink(sink Sink) {
+	t.sink = sink
+}
+
+// SetSanitizer attaches an EventSanitizer applied to query/response content
+// before it's recorded on subsequent Track* calls. Pass nil to disable
+// sanitization.
+func (t *Tracker) SetSanitizer(sanitizer *EventSanitizer) {
+	t.sanitizer = sanitizer
 }
 
 // TrackQuery tracks a user query
@@ -89,7 +142,7 @@ func (t *Tracker) TrackQuery(query string, sessionID string) {
 	event := BehaviorEvent{
 		ID:      generateEventID(),
 		Type:    BehaviorQuery,
-		Content: query,
+		Content: t.sanitizer.Sanitize(query),
 		Metadata: Metadata{
 			SessionID: sessionID,
 			Success:   true,
@@ -98,10 +151,18 @@ func (t *Tracker) TrackQuery(query string, sessionID string) {
 	}
 
 	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordQuery()
+	}
 }
 
-// TrackResponse tracks an AI response
-func (t *Tracker) TrackResponse(response string, duration time.Duration, tokenCount int, model string, sessionID string) {
+// TrackResponse tracks an AI response, recording prompt/completion token
+// counts and estimating cost from the configured pricing table for model.
+// timeToFirstToken is the latency until the first streamed token arrived;
+// pass 0 for non-streaming responses where it isn't meaningfully distinct
+// from duration.
+func (t *Tracker) TrackResponse(response string, duration time.Duration, timeToFirstToken time.Duration, usage Usage, model string, sessionID string) {
 	if !t.enabled {
 		return
 	}
@@ -109,18 +170,67 @@ func (t *Tracker) TrackResponse(response string, duration time.Duration, tokenCo
 	event := BehaviorEvent{
 		ID:      generateEventID(),
 		Type:    BehaviorResponse,
-		Content: response,
+		Content: t.sanitizer.Sanitize(response),
 		Metadata: Metadata{
-			Duration:   duration,
-			TokenCount: tokenCount,
-			Model:      model,
-			SessionID:  sessionID,
-			Success:    true,
+			Duration:           duration,
+			TimeToFirstTokenMs: timeToFirstToken.Milliseconds(),
+			TokenCount:         usage.PromptTokens + usage.CompletionTokens,
+			PromptTokens:       usage.PromptTokens,
+			CompletionTokens:   usage.CompletionTokens,
+			EstimatedCostUSD:   t.estimateCost(model, usage),
+			Model:              model,
+			SessionID:          sessionID,
+			Success:            true,
 		},
 		Timestamp: time.Now(),
 	}
 
 	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordResponse(duration, usage.PromptTokens+usage.CompletionTokens, model, event.Timestamp)
+	}
+}
+
+// estimateCost computes the dollar cost of usage for model using the
+// tracker's pricing table. Returns 0 if no pricing entry exists for model.
+func (t *Tracker) estimateCost(model string, usage Usage) float64 {
+	pricing, ok := t.pricing[model]
+	if !ok {
+		return 0
+	}
+
+	promptCost := float64(usage.PromptTokens) / 1000 * pricing.PromptPerThousand
+	completionCost := float64(usage.CompletionTokens) / 1000 * pricing.CompletionPerThousand
+	return promptCost + completionCost
+}
+
+// TrackCancelled tracks a streamed response that was aborted mid-generation
+// (e.g. the user pressed Ctrl-C), recording the partial content and however
+// many completion tokens had been produced before cancellation.
+func (t *Tracker) TrackCancelled(partialResponse string, completionTokens int, sessionID string) {
+	if !t.enabled {
+		return
+	}
+
+	event := BehaviorEvent{
+		ID:      generateEventID(),
+		Type:    BehaviorCancelled,
+		Content: t.sanitizer.Sanitize(partialResponse),
+		Metadata: Metadata{
+			TokenCount:       completionTokens,
+			CompletionTokens: completionTokens,
+			SessionID:        sessionID,
+			Success:          false,
+		},
+		Timestamp: time.Now(),
+	}
+
+	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordCancelled()
+	}
 }
 
 // TrackCommand tracks a command execution
@@ -141,6 +251,66 @@ func (t *Tracker) TrackCommand(commandName string, sessionID string) {
 	}
 
 	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordCommand(commandName, event.Timestamp)
+	}
+}
+
+// TrackToolCall tracks a single tool invocation made during an agent's
+// ReAct loop, recording how long it took and whether it succeeded.
+func (t *Tracker) TrackToolCall(toolName string, duration time.Duration, success bool, sessionID string) {
+	if !t.enabled {
+		return
+	}
+
+	event := BehaviorEvent{
+		ID:   generateEventID(),
+		Type: BehaviorToolCall,
+		Metadata: Metadata{
+			Duration:  duration,
+			ToolName:  toolName,
+			SessionID: sessionID,
+			Success:   success,
+		},
+		Timestamp: time.Now(),
+	}
+
+	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordToolCall(toolName, event.Timestamp)
+	}
+}
+
+// TrackSkillExec tracks a single skills.Executor invocation (a skill script,
+// or an external tool run on a skill's behalf), recording how long it took
+// and whether it exited cleanly. It's reported through the Sink via
+// RecordToolCall, tagged "<skillName>/<scriptName>", so skill usage rolls up
+// alongside regular tool calls rather than needing a parallel metric.
+func (t *Tracker) TrackSkillExec(skillName, scriptName string, duration time.Duration, success bool, sessionID string) {
+	if !t.enabled {
+		return
+	}
+
+	event := BehaviorEvent{
+		ID:   generateEventID(),
+		Type: BehaviorSkillExec,
+		Metadata: Metadata{
+			Duration:  duration,
+			SkillName: skillName,
+			ToolName:  scriptName,
+			SessionID: sessionID,
+			Success:   success,
+		},
+		Timestamp: time.Now(),
+	}
+
+	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordToolCall(skillName+"/"+scriptName, event.Timestamp)
+	}
 }
 
 // TrackError tracks an error event
@@ -161,6 +331,10 @@ func (t *Tracker) TrackError(errorMsg string, sessionID string) {
 	}
 
 	t.addEvent(event)
+
+	if t.sink != nil {
+		t.sink.RecordError()
+	}
 }
 
 // TrackSessionEnd tracks session end
@@ -183,6 +357,10 @@ func (t *Tracker) TrackSessionEnd() {
 
 	t.addEvent(event)
 
+	if t.sink != nil {
+		t.sink.RecordSession()
+	}
+
 	// Persist events to file
 	if err := t.persist(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to persist events: %v\n", err)
@@ -206,69 +384,42 @@ func (t *Tracker) addEvent(event BehaviorEvent) {
 	}
 }
 
-// persist saves events to disk
+// persist flushes buffered events to the store and blocks until the
+// underlying writer goroutine has processed them. It returns an error if the
+// store is already closed; a failure while writing a batch to disk is logged
+// to stderr by the store's writer goroutine and is not returned here.
 func (t *Tracker) persist() error {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-
-	if len(t.events) == 0 {
-		return nil
-	}
-
-	// Get today's file path
-	filePath := t.getTodayFilePath()
-
-	// Read existing events
-	var allEvents []BehaviorEvent
-	if data, err := os.ReadFile(filePath); err == nil {
-		if err := json.Unmarshal(data, &allEvents); err != nil {
-			return fmt.Errorf("failed to unmarshal existing events: %w", err)
-		}
-	}
-
-	// Append new events
-	allEvents = append(allEvents, t.events...)
-
-	// Write back to file
-	data, err := json.MarshalIndent(allEvents, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal events: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write events file: %w", err)
-	}
-
-	// Clear in-memory events after successful persist
+	t.mu.Lock()
+	pending := t.events
 	t.events = make([]BehaviorEvent, 0)
+	t.mu.Unlock()
 
-	return nil
-}
-
-// loadTodayEvents loads events from today's file
-func (t *Tracker) loadTodayEvents() error {
-	filePath := t.getTodayFilePath()
-
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // File doesn't exist yet, that's okay
+	for _, event := range pending {
+		if err := t.store.Append(event); err != nil {
+			return fmt.Errorf("failed to append event to store: %w", err)
 		}
-		return fmt.Errorf("failed to read events file: %w", err)
 	}
 
-	var events []BehaviorEvent
-	if err := json.Unmarshal(data, &events); err != nil {
-		return fmt.Errorf("failed to unmarshal events: %w", err)
-	}
-
-	return nil
+	return t.store.Flush()
 }
 
-// getTodayFilePath returns the file path for today's events
+// getTodayFilePath returns the file path for today's live events shard.
 func (t *Tracker) getTodayFilePath() string {
 	today := time.Now().Format("2006-01-02")
-	return filepath.Join(t.dataPath, fmt.Sprintf("events-%s.json", today))
+	return filepath.Join(t.dataPath, fmt.Sprintf("events-%s.jsonl", today))
+}
+
+// ApplyRetention deletes event shards dated entirely before retentionDays
+// ago, meant to be called once at startup so long-lived installs don't
+// accumulate unbounded history. A non-positive retentionDays disables
+// pruning.
+func (t *Tracker) ApplyRetention(retentionDays int) error {
+	if !t.enabled || retentionDays <= 0 {
+		return nil
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	return t.store.Prune(cutoff)
 }
 
 // GetSessionID returns the current session ID
@@ -285,3 +436,78 @@ func generateSessionID() string {
 func generateEventID() string {
 	return fmt.Sprintf("event-%d", time.Now().UnixNano())
 }
+
+// ModelSummary aggregates response events for a single model on a single
+// day.
+type ModelSummary struct {
+	Model            string
+	Responses        int
+	PromptTokens     int
+	CompletionTokens int
+	EstimatedCostUSD float64
+}
+
+// DaySummary aggregates ModelSummary roll-ups for every model seen on Date.
+type DaySummary struct {
+	Date   string
+	Models map[string]*ModelSummary
+}
+
+// Summary aggregates the events covering period ("today", "week", or
+// "month") into per-day / per-model roll-ups, streaming through the store
+// via Between rather than re-reading every raw shard file each time.
+func (t *Tracker) Summary(period string) ([]DaySummary, error) {
+	var daysBack int
+	switch period {
+	case "today":
+		daysBack = 0
+	case "week":
+		daysBack = 7
+	case "month":
+		daysBack = 30
+	default:
+		return nil, fmt.Errorf("invalid period: %s (must be today, week, or month)", period)
+	}
+
+	end := time.Now()
+	start := dateOnly(end.AddDate(0, 0, -daysBack))
+
+	days := make(map[string]*DaySummary)
+	var order []string
+	for event := range t.store.Between(start, end) {
+		if event.Type != BehaviorResponse {
+			continue
+		}
+
+		dateStr := event.Timestamp.Format("2006-01-02")
+		day, ok := days[dateStr]
+		if !ok {
+			day = &DaySummary{Date: dateStr, Models: make(map[string]*ModelSummary)}
+			days[dateStr] = day
+			order = append(order, dateStr)
+		}
+
+		model := event.Metadata.Model
+		if model == "" {
+			model = "unknown"
+		}
+
+		ms, ok := day.Models[model]
+		if !ok {
+			ms = &ModelSummary{Model: model}
+			day.Models[model] = ms
+		}
+		ms.Responses++
+		ms.PromptTokens += event.Metadata.PromptTokens
+		ms.CompletionTokens += event.Metadata.CompletionTokens
+		ms.EstimatedCostUSD += event.Metadata.EstimatedCostUSD
+	}
+
+	sort.Strings(order)
+	summaries := make([]DaySummary, 0, len(order))
+	for _, dateStr := range order {
+		summaries = append(summaries, *days[dateStr])
+	}
+
+	return summaries, nil
+}
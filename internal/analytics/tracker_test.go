@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/warm3snow/WorkWise/internal/config"
 )
 
 func TestNewTracker(t *testing.T) {
@@ -69,15 +71,15 @@ func TestTrackResponse(t *testing.T) {
 	sessionID := "test-session"
 	response := "test response"
 	duration := 100 * time.Millisecond
-	tokenCount := 50
+	usage := Usage{PromptTokens: 30, CompletionTokens: 20}
 	model := "test-model"
-	
-	tracker.TrackResponse(response, duration, tokenCount, model, sessionID)
-	
+
+	tracker.TrackResponse(response, duration, 0, usage, model, sessionID)
+
 	if len(tracker.events) != 1 {
 		t.Errorf("Expected 1 event, got %d", len(tracker.events))
 	}
-	
+
 	event := tracker.events[0]
 	if event.Type != BehaviorResponse {
 		t.Errorf("Expected type %s, got %s", BehaviorResponse, event.Type)
@@ -85,14 +87,33 @@ func TestTrackResponse(t *testing.T) {
 	if event.Metadata.Duration != duration {
 		t.Errorf("Expected duration %v, got %v", duration, event.Metadata.Duration)
 	}
-	if event.Metadata.TokenCount != tokenCount {
-		t.Errorf("Expected token count %d, got %d", tokenCount, event.Metadata.TokenCount)
+	if event.Metadata.TokenCount != usage.PromptTokens+usage.CompletionTokens {
+		t.Errorf("Expected token count %d, got %d", usage.PromptTokens+usage.CompletionTokens, event.Metadata.TokenCount)
 	}
 	if event.Metadata.Model != model {
 		t.Errorf("Expected model %s, got %s", model, event.Metadata.Model)
 	}
 }
 
+func TestTrackResponseEstimatesCost(t *testing.T) {
+	tmpDir := t.TempDir()
+	tracker, err := NewTracker(true, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	tracker.SetPricing(map[string]config.ModelPricing{
+		"test-model": {PromptPerThousand: 1.0, CompletionPerThousand: 2.0},
+	})
+
+	tracker.TrackResponse("response", time.Millisecond, 0, Usage{PromptTokens: 1000, CompletionTokens: 500}, "test-model", "session")
+
+	got := tracker.events[0].Metadata.EstimatedCostUSD
+	want := 1.0 + 0.5*2.0
+	if got != want {
+		t.Errorf("Expected estimated cost %.4f, got %.4f", want, got)
+	}
+}
+
 func TestPersist(t *testing.T) {
 	tmpDir := t.TempDir()
 	tracker, err := NewTracker(true, tmpDir)
@@ -127,7 +148,7 @@ func TestTrackerDisabled(t *testing.T) {
 	
 	// These should not panic or error when disabled
 	tracker.TrackQuery("test", "session")
-	tracker.TrackResponse("test", 0, 0, "", "session")
+	tracker.TrackResponse("test", 0, 0, Usage{}, "", "session")
 	tracker.TrackCommand("test", "session")
 	tracker.TrackError("test", "session")
 	tracker.TrackSessionEnd()
@@ -143,7 +164,7 @@ func TestGetTodayFilePath(t *testing.T) {
 	
 	filePath := tracker.getTodayFilePath()
 	today := time.Now().Format("2006-01-02")
-	expected := filepath.Join(tmpDir, "events-"+today+".json")
+	expected := filepath.Join(tmpDir, "events-"+today+".jsonl")
 	
 	if filePath != expected {
 		t.Errorf("Expected file path %s, got %s", expected, filePath)
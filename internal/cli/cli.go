@@ -4,14 +4,22 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/urfave/cli/v2"
 	"github.com/warm3snow/WorkWise/internal/agent"
 	"github.com/warm3snow/WorkWise/internal/analytics"
+	"github.com/warm3snow/WorkWise/internal/analytics/prom"
 	"github.com/warm3snow/WorkWise/internal/config"
+	"github.com/warm3snow/WorkWise/internal/llm"
+	"github.com/warm3snow/WorkWise/pkg/skills"
 )
 
 // App represents the CLI application
@@ -20,9 +28,14 @@ type App struct {
 	version   string
 	buildTime string
 	gitCommit string
-	agent     *agent.Agent
 	tracker   *analytics.Tracker
 	analyzer  *analytics.Analyzer
+	skills    *skills.Loader
+
+	// agentMu guards agent and config against concurrent access from the
+	// config watcher's reload goroutine while the REPL loop is running.
+	agentMu sync.Mutex
+	agent   *agent.Agent
 }
 
 // NewApp creates a new CLI application
@@ -33,11 +46,30 @@ func NewApp(cfg *config.Config, version, buildTime, gitCommit string) *App {
 		fmt.Fprintf(os.Stderr, "Warning: failed to initialize analytics tracker: %v\n", err)
 		tracker, _ = analytics.NewTracker(false, "") // Disabled tracker as fallback
 	}
+	tracker.SetPricing(cfg.AI.Pricing)
+	if err := tracker.ApplyRetention(cfg.Extensions.AnalyticsRetentionDays); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to apply analytics retention policy: %v\n", err)
+	}
 
-	// Initialize analyzer
+	sanitizer := analytics.NewEventSanitizer(cfg.Extensions.AnalyticsPrivacy)
+	tracker.SetSanitizer(sanitizer)
+
+	// Initialize analyzer. If `workwise analytics migrate` has produced an
+	// indexed analytics.db, read from that instead of the raw JSONL shards,
+	// so the migration's output is actually on a query path. Falls back to
+	// the JSONL shards if analytics.db can't be opened.
 	analyzer := analytics.NewAnalyzer(cfg.Extensions.AnalyticsPath)
+	dbPath := filepath.Join(cfg.Extensions.AnalyticsPath, "analytics.db")
+	if _, err := os.Stat(dbPath); err == nil {
+		if boltStore, err := analytics.NewBoltStore(dbPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open migrated analytics store %q, using JSONL shards: %v\n", dbPath, err)
+		} else {
+			analyzer = analytics.NewAnalyzerWithStore(boltStore)
+		}
+	}
+	analyzer.SetPrivacyActive(sanitizer.Active())
 
-	return &App{
+	app := &App{
 		config:    cfg,
 		version:   version,
 		buildTime: buildTime,
@@ -45,6 +77,44 @@ func NewApp(cfg *config.Config, version, buildTime, gitCommit string) *App {
 		tracker:   tracker,
 		analyzer:  analyzer,
 	}
+
+	if cfg.Extensions.SkillsEnabled && len(cfg.Extensions.SkillsPaths) > 0 {
+		app.skills = skills.NewLoader(cfg.Extensions.SkillsPaths)
+		if err := app.skills.LoadAll(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load skills: %v\n", err)
+		}
+	}
+
+	return app
+}
+
+// before runs once, ahead of whichever command was invoked, and starts the
+// optional Prometheus metrics endpoint: from --metrics-addr if set, else
+// from the analytics_metrics_listen config value. The Tracker is wired to
+// push live updates to it on every Track* call, giving operators an
+// alternative to scraping the daily JSON files.
+func (a *App) before(c *cli.Context) error {
+	addr := c.String("metrics-addr")
+	if addr == "" {
+		addr = a.config.Extensions.AnalyticsMetricsListen
+	}
+	if addr == "" {
+		return nil
+	}
+
+	exporter := prom.NewExporter()
+	a.tracker.SetSink(exporter)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter.Handler())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: metrics server stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Metrics available at http://%s/metrics\n", addr)
+	return nil
 }
 
 // Run starts the CLI application
@@ -66,7 +136,13 @@ func (a *App) Run(args []string) error {
 				Usage:   "Path to configuration file",
 				EnvVars: []string{"WORKWISE_CONFIG"},
 			},
+			&cli.StringFlag{
+				Name:    "metrics-addr",
+				Usage:   "Serve Prometheus metrics at http://<addr>/metrics (e.g. :9090); disabled if empty",
+				EnvVars: []string{"WORKWISE_METRICS_ADDR"},
+			},
 		},
+		Before:   a.before,
 		Commands: []*cli.Command{
 			{
 				Name:    "chat",
@@ -121,6 +197,11 @@ func (a *App) Run(args []string) error {
 						Usage:  "Show this month's statistics",
 						Action: a.statsMonth,
 					},
+					{
+						Name:   "reset-baseline",
+						Usage:  "Reset the rolling anomaly-detection baselines",
+						Action: a.statsResetBaseline,
+					},
 				},
 				Action: a.statsToday, // Default to today
 			},
@@ -138,6 +219,23 @@ func (a *App) Run(args []string) error {
 				},
 				Action: a.reportCommand,
 			},
+			{
+				Name:  "analytics",
+				Usage: "Manage the analytics data store",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "migrate",
+						Usage: "Migrate events-YYYY-MM-DD.json shards into an indexed BoltDB store",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "dest",
+								Usage: "Destination BoltDB file (default: <analytics_path>/analytics.db)",
+							},
+						},
+						Action: a.analyticsMigrateCommand,
+					},
+				},
+			},
 		},
 		Action: a.defaultAction,
 	}
@@ -165,6 +263,17 @@ func (a *App) chatCommand(c *cli.Context) error {
 	if err := a.initAgent(); err != nil {
 		return fmt.Errorf("failed to initialize agent: %w", err)
 	}
+	defer a.closeAgent()
+
+	// Hot-reload config.yaml and the skills directories in the background
+	// so editing them doesn't require restarting this REPL.
+	watcher, err := config.NewWatcher(a.config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: config hot-reload disabled: %v\n", err)
+	} else {
+		defer watcher.Close()
+		go a.watchConfig(watcher)
+	}
 
 	scanner := bufio.NewScanner(os.Stdin)
 	ctx := context.Background()
@@ -184,6 +293,9 @@ func (a *App) chatCommand(c *cli.Context) error {
 		switch strings.ToLower(input) {
 		case "exit", "quit", "q":
 			a.tracker.TrackSessionEnd()
+			if err := a.tracker.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to close analytics tracker: %v\n", err)
+			}
 			fmt.Println("Goodbye!")
 			return nil
 		case "help", "?":
@@ -199,21 +311,69 @@ func (a *App) chatCommand(c *cli.Context) error {
 		// Track query
 		a.tracker.TrackQuery(input, a.tracker.GetSessionID())
 
-		// Process user input with agent
+		// Stream the response, rendering tokens as they arrive. Ctrl-C
+		// cancels the in-flight generation without exiting the REPL: it's
+		// wired to a context scoped to this single turn, not to ctx itself.
 		startTime := time.Now()
-		response, err := a.agent.Process(ctx, input)
-		duration := time.Since(startTime)
-		
+		a.agentMu.Lock()
+		currentAgent := a.agent
+		a.agentMu.Unlock()
+
+		turnCtx, cancelTurn := context.WithCancel(ctx)
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		stopWatchingInterrupt := make(chan struct{})
+		go func() {
+			select {
+			case <-interrupt:
+				cancelTurn()
+			case <-stopWatchingInterrupt:
+			}
+		}()
+
+		chunks, err := currentAgent.ProcessStream(turnCtx, input)
 		if err != nil {
+			signal.Stop(interrupt)
+			close(stopWatchingInterrupt)
+			cancelTurn()
 			a.tracker.TrackError(err.Error(), a.tracker.GetSessionID())
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			continue
 		}
 
+		var response strings.Builder
+		var usage llm.Usage
+		var timeToFirstToken time.Duration
+		first := true
+		for chunk := range chunks {
+			if first {
+				timeToFirstToken = time.Since(startTime)
+				first = false
+			}
+			fmt.Print(chunk.Content)
+			response.WriteString(chunk.Content)
+			usage = chunk.Usage
+		}
+		cancelled := turnCtx.Err() != nil
+		signal.Stop(interrupt)
+		close(stopWatchingInterrupt)
+		cancelTurn()
+		duration := time.Since(startTime)
+		fmt.Println()
+
+		if cancelled {
+			a.tracker.TrackCancelled(response.String(), usage.CompletionTokens, a.tracker.GetSessionID())
+			fmt.Println("(generation cancelled)")
+			fmt.Println()
+			continue
+		}
+
 		// Track response
-		a.tracker.TrackResponse(response, duration, 0, a.config.AI.Model, a.tracker.GetSessionID())
+		a.tracker.TrackResponse(response.String(), duration, timeToFirstToken, analytics.Usage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+		}, a.config.AI.Model, a.tracker.GetSessionID())
 
-		fmt.Println(response)
 		fmt.Println()
 	}
 
@@ -236,6 +396,7 @@ func (a *App) askCommand(c *cli.Context) error {
 	if err := a.initAgent(); err != nil {
 		return fmt.Errorf("failed to initialize agent: %w", err)
 	}
+	defer a.closeAgent()
 
 	// Track query
 	sessionID := a.tracker.GetSessionID()
@@ -243,17 +404,23 @@ func (a *App) askCommand(c *cli.Context) error {
 
 	ctx := context.Background()
 	startTime := time.Now()
-	response, err := a.agent.Process(ctx, question)
+	response, usage, err := a.agent.Process(ctx, question)
 	duration := time.Since(startTime)
-	
+
 	if err != nil {
 		a.tracker.TrackError(err.Error(), sessionID)
 		return fmt.Errorf("error processing question: %w", err)
 	}
 
 	// Track response
-	a.tracker.TrackResponse(response, duration, 0, a.config.AI.Model, sessionID)
+	a.tracker.TrackResponse(response, duration, 0, analytics.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	}, a.config.AI.Model, sessionID)
 	a.tracker.TrackSessionEnd()
+	if err := a.tracker.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close analytics tracker: %v\n", err)
+	}
 
 	fmt.Println(response)
 	return nil
@@ -297,13 +464,92 @@ func (a *App) versionCommand(c *cli.Context) error {
 
 // initAgent initializes the AI agent
 func (a *App) initAgent() error {
+	a.agentMu.Lock()
+	defer a.agentMu.Unlock()
+
 	if a.agent != nil {
 		return nil
 	}
 
-	var err error
-	a.agent, err = agent.NewAgent(a.config)
-	return err
+	newAgent, err := agent.NewAgent(a.config)
+	if err != nil {
+		return err
+	}
+
+	newAgent.SetTracker(a.tracker)
+	a.agent = newAgent
+	return nil
+}
+
+// closeAgent disconnects the current agent's MCP servers on app shutdown, so
+// the subprocesses initAgent/applyConfig spawned don't outlive the process.
+func (a *App) closeAgent() {
+	a.agentMu.Lock()
+	current := a.agent
+	a.agentMu.Unlock()
+
+	if current == nil {
+		return
+	}
+	if err := current.Close(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close agent's MCP servers: %v\n", err)
+	}
+}
+
+// watchConfig consumes reload events from watcher for the lifetime of the
+// interactive session, rebuilding the agent's model.ChatModel and reloading
+// skills whenever config.yaml or a skills directory changes. A failed
+// reload is reported but leaves the currently running agent untouched.
+func (a *App) watchConfig(watcher *config.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events():
+			if !ok {
+				return
+			}
+			a.applyConfig(event.Config)
+		case err, ok := <-watcher.Errors():
+			if !ok {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "\nWarning: %v\n", err)
+		}
+	}
+}
+
+// applyConfig swaps in a newly reloaded configuration: it rebuilds the
+// agent (and therefore the underlying LLM client) and reloads skills. If
+// rebuilding the agent fails, the previous configuration and agent stay
+// active so a bad edit can't kill the running session. The replaced agent's
+// MCP servers are disconnected after the swap, so a reload doesn't leak an
+// MCP subprocess every time it runs.
+func (a *App) applyConfig(cfg *config.Config) {
+	newAgent, err := agent.NewAgent(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nWarning: config reload produced an invalid agent, keeping previous configuration: %v\n", err)
+		return
+	}
+	newAgent.SetTracker(a.tracker)
+
+	a.agentMu.Lock()
+	oldAgent := a.agent
+	a.config = cfg
+	a.agent = newAgent
+	a.agentMu.Unlock()
+
+	if oldAgent != nil {
+		if err := oldAgent.Close(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "\nWarning: failed to close previous agent's MCP servers: %v\n", err)
+		}
+	}
+
+	if a.skills != nil {
+		if err := a.skills.LoadAll(); err != nil {
+			fmt.Fprintf(os.Stderr, "\nWarning: failed to reload skills: %v\n", err)
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "\nConfiguration reloaded")
 }
 
 // printHelp prints available commands
@@ -328,6 +574,7 @@ func (a *App) statsToday(c *cli.Context) error {
 	}
 
 	fmt.Println(analytics.FormatStatistics(stats))
+	a.printStorageUsage()
 	return nil
 }
 
@@ -344,6 +591,7 @@ func (a *App) statsWeek(c *cli.Context) error {
 	}
 
 	fmt.Println(analytics.FormatStatistics(stats))
+	a.printStorageUsage()
 	return nil
 }
 
@@ -360,6 +608,47 @@ func (a *App) statsMonth(c *cli.Context) error {
 	}
 
 	fmt.Println(analytics.FormatStatistics(stats))
+	a.printStorageUsage()
+	return nil
+}
+
+// printStorageUsage prints the on-disk size of each analytics event shard.
+// It prints nothing when there are no shards yet (e.g. analytics was just
+// enabled), and logs a warning instead of failing the stats command if
+// listing the shards returns an error.
+func (a *App) printStorageUsage() {
+	sizes, err := analytics.Sizes(a.config.Extensions.AnalyticsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read analytics storage usage: %v\n", err)
+		return
+	}
+	if len(sizes) == 0 {
+		return
+	}
+
+	var total int64
+	fmt.Println("\nStorage Usage:")
+	for _, s := range sizes {
+		fmt.Printf("  %s: %s\n", s.Name, humanize.Bytes(uint64(s.Bytes)))
+		total += s.Bytes
+	}
+	fmt.Printf("  Total: %s\n", humanize.Bytes(uint64(total)))
+}
+
+// statsResetBaseline clears the persisted EWMA baselines used for anomaly
+// detection, so the next stats/report command starts re-learning each
+// metric from scratch.
+func (a *App) statsResetBaseline(c *cli.Context) error {
+	if !a.config.Extensions.AnalyticsEnabled {
+		fmt.Println("Analytics is not enabled. Enable it in your config file.")
+		return nil
+	}
+
+	if err := a.analyzer.ResetBaselines(); err != nil {
+		return fmt.Errorf("failed to reset baselines: %w", err)
+	}
+
+	fmt.Println("Anomaly detection baselines reset")
 	return nil
 }
 
@@ -392,3 +681,28 @@ func (a *App) reportCommand(c *cli.Context) error {
 	fmt.Println(analytics.FormatStatistics(stats))
 	return nil
 }
+
+// analyticsMigrateCommand migrates the existing events-YYYY-MM-DD.json
+// shards into a BoltStore, the indexed backend Analyzer can stream from
+// with bounded memory for large histories.
+func (a *App) analyticsMigrateCommand(c *cli.Context) error {
+	dest := c.String("dest")
+	if dest == "" {
+		dest = filepath.Join(a.config.Extensions.AnalyticsPath, "analytics.db")
+	}
+
+	source := analytics.NewJSONStore(a.config.Extensions.AnalyticsPath)
+	destStore, err := analytics.NewBoltStore(dest)
+	if err != nil {
+		return fmt.Errorf("failed to open destination store: %w", err)
+	}
+	defer destStore.Close()
+
+	migrated, err := analytics.Migrate(source, destStore)
+	if err != nil {
+		return fmt.Errorf("migration failed after copying %d events: %w", migrated, err)
+	}
+
+	fmt.Printf("Migrated %d events to %s\n", migrated, dest)
+	return nil
+}
@@ -28,8 +28,24 @@ type AIConfig struct {
 	Model    string `yaml:"model"`
 	BaseURL  string `yaml:"base_url,omitempty"`
 
+	// SocketPath, when set, dials the Ollama/OpenAI-compatible backend over
+	// a Unix domain socket instead of TCP, taking precedence over BaseURL.
+	// Lets WorkWise talk to a locally sandboxed llama.cpp/Ollama instance
+	// without opening a TCP port.
+	SocketPath string `yaml:"socket_path,omitempty"`
+
 	// Agent configuration (using eino framework)
 	Agent AgentConfig `yaml:"agent"`
+
+	// Pricing maps a model name to its per-1K-token cost, used by the
+	// analytics tracker to estimate spend on each tracked response.
+	Pricing map[string]ModelPricing `yaml:"pricing,omitempty"`
+}
+
+// ModelPricing describes the per-1K-token cost of a single model.
+type ModelPricing struct {
+	PromptPerThousand     float64 `yaml:"prompt_per_thousand"`
+	CompletionPerThousand float64 `yaml:"completion_per_thousand"`
 }
 
 // AgentConfig contains agent framework configuration
@@ -58,10 +74,56 @@ type ExtensionsConfig struct {
 	SkillsEnabled bool     `yaml:"skills_enabled"`
 	SkillsPaths   []string `yaml:"skills_paths,omitempty"`
 
+	// SkillsTrustedKeys lists hex-encoded ed25519 public keys authorized to
+	// sign skills installed via skills.Loader.InstallFromURL/InstallFromHub.
+	SkillsTrustedKeys []string `yaml:"skills_trusted_keys,omitempty"`
+
+	// SkillsHubURL is the base URL used to resolve skills.Loader.InstallFromHub
+	// names to tarball URLs.
+	SkillsHubURL string `yaml:"skills_hub_url,omitempty"`
+
 	// Desktop integration for Windows/Mac
 	DesktopEnabled  bool   `yaml:"desktop_enabled"`
 	DesktopHotkey   string `yaml:"desktop_hotkey,omitempty"`
 	DesktopPosition string `yaml:"desktop_position,omitempty"`
+
+	// Behavior analytics tracking
+	AnalyticsEnabled bool   `yaml:"analytics_enabled"`
+	AnalyticsPath    string `yaml:"analytics_path,omitempty"`
+
+	// AnalyticsRetentionDays, when positive, prunes analytics shards older
+	// than this many days on startup. Zero disables pruning; DefaultConfig
+	// sets this to 90, so pruning is on unless explicitly zeroed out.
+	AnalyticsRetentionDays int `yaml:"analytics_retention_days,omitempty"`
+
+	// AnalyticsPrivacy controls how much of a query/response's raw content
+	// is retained in tracked events.
+	AnalyticsPrivacy AnalyticsPrivacyConfig `yaml:"analytics_privacy,omitempty"`
+
+	// AnalyticsMetricsListen, when set, starts a Prometheus metrics server
+	// on this address (e.g. ":9090") fed live by the Tracker, alongside the
+	// JSON file history. Empty disables it; the --metrics-addr CLI flag
+	// takes precedence over this when both are set.
+	AnalyticsMetricsListen string `yaml:"analytics_metrics_listen,omitempty"`
+}
+
+// AnalyticsPrivacyConfig controls truncation and redaction of the raw
+// content recorded in BehaviorEvents.
+type AnalyticsPrivacyConfig struct {
+	// MaxContentBytes truncates tracked content to this many bytes,
+	// appending an ellipsis marker. Zero or negative disables truncation.
+	MaxContentBytes int `yaml:"max_content_bytes,omitempty"`
+
+	// RedactPatterns enables regex-based redaction of common secret/PII
+	// shapes (emails, API keys, bearer tokens, AWS keys, IPs, credit card
+	// numbers) before content is persisted.
+	RedactPatterns bool `yaml:"redact_patterns"`
+
+	// HashOnly, when true, replaces tracked content with its SHA-256 hash
+	// plus length, so aggregate stats (counts, token totals) still work
+	// without retaining any recoverable text at all. Takes precedence over
+	// MaxContentBytes/RedactPatterns.
+	HashOnly bool `yaml:"hash_only"`
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -86,9 +148,16 @@ func DefaultConfig() *Config {
 			HistoryFile: filepath.Join(homeDir, ".workwise_history"),
 		},
 		Extensions: ExtensionsConfig{
-			MCPEnabled:     false,
-			SkillsEnabled:  false,
-			DesktopEnabled: false,
+			MCPEnabled:             false,
+			SkillsEnabled:          false,
+			DesktopEnabled:         false,
+			AnalyticsEnabled:       false,
+			AnalyticsPath:          filepath.Join(homeDir, ".workwise", "analytics"),
+			AnalyticsRetentionDays: 90,
+			AnalyticsPrivacy: AnalyticsPrivacyConfig{
+				MaxContentBytes: 4096,
+				RedactPatterns:  true,
+			},
 		},
 	}
 }
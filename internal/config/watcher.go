@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceInterval coalesces bursts of filesystem events (e.g. an editor
+// that writes a file in several steps) into a single reload.
+const debounceInterval = 250 * time.Millisecond
+
+// Event carries a successfully reloaded and validated configuration.
+type Event struct {
+	Config *Config
+}
+
+// Watcher watches the config file and every directory in
+// Extensions.SkillsPaths for changes, reloading the configuration whenever
+// one changes. A failed reload (e.g. invalid YAML) is reported on Errors()
+// and the previously loaded configuration stays active, so a bad edit can't
+// kill a running session.
+type Watcher struct {
+	fsw     *fsnotify.Watcher
+	events  chan Event
+	errors  chan error
+	current *Config
+}
+
+// NewWatcher creates a Watcher seeded with the already-loaded cfg, watching
+// its source file plus every configured skills directory.
+func NewWatcher(cfg *Config) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filesystem watcher: %w", err)
+	}
+
+	configPath := getConfigPath()
+	if err := fsw.Add(filepath.Dir(configPath)); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	for _, path := range cfg.Extensions.SkillsPaths {
+		// A missing skills directory shouldn't stop the watcher; it may be
+		// created later, and other watched paths are still useful. fsnotify
+		// watches aren't recursive, so every directory beneath the skills
+		// path is added too, reaching each skill's own SKILL.md and assets.
+		_ = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				_ = fsw.Add(p)
+			}
+			return nil
+		})
+	}
+
+	w := &Watcher{
+		fsw:     fsw,
+		events:  make(chan Event, 1),
+		errors:  make(chan error, 1),
+		current: cfg,
+	}
+
+	go w.run(configPath)
+
+	return w, nil
+}
+
+// Events returns the channel of successfully reloaded configurations.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Errors returns the channel of reload failures.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching and releases the underlying OS resources.
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+func (w *Watcher) run(configPath string) {
+	var timer *time.Timer
+
+	scheduleReload := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(debounceInterval, func() {
+			cfg, err := Load()
+			if err != nil {
+				w.errors <- fmt.Errorf("config reload failed, keeping previous configuration: %w", err)
+				return
+			}
+			w.current = cfg
+			w.events <- Event{Config: cfg}
+		})
+	}
+
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// The config file itself, or any file under a watched skills
+			// directory (SKILL.md and its assets), can trigger a reload.
+			if event.Name == configPath || filepath.Dir(event.Name) != filepath.Dir(configPath) {
+				scheduleReload()
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.errors <- err
+		}
+	}
+}
@@ -3,32 +3,79 @@ package llm
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 
+	"github.com/cloudwego/eino-ext/components/model/claude"
 	"github.com/cloudwego/eino-ext/components/model/ollama"
 	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/warm3snow/WorkWise/internal/config"
+	mcpclient "github.com/warm3snow/WorkWise/internal/mcp"
 )
 
-// NewClient creates a new LLM client based on configuration
+// unixSocketBaseURL is the placeholder host used in BaseURL when dialing a
+// Unix domain socket; the socket path itself is baked into the http.Client's
+// DialContext, so the host/scheme here is never actually resolved.
+const unixSocketBaseURL = "http://unix"
+
+// unixSocketHTTPClient returns an *http.Client whose transport dials
+// socketPath instead of resolving the request's host over TCP.
+func unixSocketHTTPClient(socketPath string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+}
+
+// NewClient creates a new LLM client based on configuration, along with an
+// MCP manager if tool integration is enabled. The manager is nil when
+// Extensions.MCPEnabled is false; otherwise it is returned for every
+// provider, though only the Anthropic client has the MCP tools bound to it.
+// Callers that don't need tool dispatch can ignore it.
 // Uses cloudwego/eino-ext for LLM provider integration
-func NewClient(cfg *config.Config) (model.ChatModel, error) {
-	// API key is required for OpenAI but not for Ollama
-	if cfg.AI.Provider == "openai" && cfg.AI.APIKey == "" {
-		return nil, fmt.Errorf("API key is required. Please set WORKWISE_API_KEY environment variable or configure it in config file")
+func NewClient(cfg *config.Config) (model.ChatModel, *mcpclient.Manager, error) {
+	// API key is required for OpenAI and Anthropic but not for Ollama
+	if (cfg.AI.Provider == "openai" || cfg.AI.Provider == "anthropic") && cfg.AI.APIKey == "" {
+		return nil, nil, fmt.Errorf("API key is required. Please set WORKWISE_API_KEY environment variable or configure it in config file")
+	}
+
+	var mgr *mcpclient.Manager
+	if cfg.Extensions.MCPEnabled {
+		if len(cfg.Extensions.MCPServers) == 0 {
+			return nil, nil, fmt.Errorf("mcp_enabled is true but no mcp_servers are configured")
+		}
+
+		var err error
+		mgr, err = mcpclient.Connect(context.Background(), cfg.Extensions.MCPServers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to configured MCP servers: %w", err)
+		}
 	}
 
+	var (
+		chatModel model.ChatModel
+		err       error
+	)
 	switch cfg.AI.Provider {
 	case "openai":
-		return newOpenAIClient(cfg)
+		chatModel, err = newOpenAIClient(cfg)
 	case "ollama":
-		return newOllamaClient(cfg)
-	// Future providers can be added here
-	// case "anthropic":
-	//     return newAnthropicClient(cfg)
+		chatModel, err = newOllamaClient(cfg)
+	case "anthropic":
+		chatModel, err = newAnthropicClient(cfg, mgr)
 	default:
-		return nil, fmt.Errorf("unsupported provider: %s", cfg.AI.Provider)
+		return nil, nil, fmt.Errorf("unsupported provider: %s", cfg.AI.Provider)
 	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return chatModel, mgr, nil
 }
 
 // newOpenAIClient creates an OpenAI client using eino-ext
@@ -38,8 +85,12 @@ func newOpenAIClient(cfg *config.Config) (model.ChatModel, error) {
 		Model:  cfg.AI.Model,
 	}
 
-	// Set base URL if provided (for compatible APIs)
-	if cfg.AI.BaseURL != "" {
+	// A socket path takes precedence over BaseURL, letting this run against
+	// a locally sandboxed OpenAI-compatible server without opening a TCP port.
+	if cfg.AI.SocketPath != "" {
+		clientConfig.BaseURL = unixSocketBaseURL
+		clientConfig.HTTPClient = unixSocketHTTPClient(cfg.AI.SocketPath)
+	} else if cfg.AI.BaseURL != "" {
 		clientConfig.BaseURL = cfg.AI.BaseURL
 	}
 
@@ -63,10 +114,15 @@ func newOllamaClient(cfg *config.Config) (model.ChatModel, error) {
 		Model: cfg.AI.Model,
 	}
 
-	// Set base URL if provided, otherwise use default Ollama endpoint
-	if cfg.AI.BaseURL != "" {
+	// A socket path takes precedence over BaseURL, letting this run against a
+	// locally sandboxed Ollama/llama.cpp instance without opening a TCP port.
+	switch {
+	case cfg.AI.SocketPath != "":
+		clientConfig.BaseURL = unixSocketBaseURL
+		clientConfig.HTTPClient = unixSocketHTTPClient(cfg.AI.SocketPath)
+	case cfg.AI.BaseURL != "":
 		clientConfig.BaseURL = cfg.AI.BaseURL
-	} else {
+	default:
 		// Default Ollama base URL
 		clientConfig.BaseURL = "http://localhost:11434"
 	}
@@ -86,9 +142,38 @@ func newOllamaClient(cfg *config.Config) (model.ChatModel, error) {
 	return client, nil
 }
 
-// Future: Add support for other providers
-// func newAnthropicClient(cfg *config.Config) (model.ChatModel, error) {
-//     // Implementation for Anthropic Claude using eino-ext
-//     // This will be added when eino-ext supports Anthropic
-//     return nil, fmt.Errorf("anthropic provider not yet implemented")
-// }
+// newAnthropicClient creates an Anthropic (Claude) client using eino-ext,
+// binding it to any tools discovered from configured MCP servers so the
+// model can emit tool_use turns that the agent loop routes back to mgr.
+func newAnthropicClient(cfg *config.Config, mgr *mcpclient.Manager) (model.ChatModel, error) {
+	clientConfig := &claude.Config{
+		APIKey: cfg.AI.APIKey,
+		Model:  cfg.AI.Model,
+	}
+
+	// Set base URL if provided (for compatible APIs / proxies)
+	if cfg.AI.BaseURL != "" {
+		clientConfig.BaseURL = &cfg.AI.BaseURL
+	}
+
+	// Set temperature
+	if cfg.AI.Agent.Temperature > 0 {
+		temp := float32(cfg.AI.Agent.Temperature)
+		clientConfig.Temperature = &temp
+	}
+
+	client, err := claude.NewChatModel(context.Background(), clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Anthropic client: %w", err)
+	}
+
+	if mgr == nil || !mgr.HasTools() {
+		return client, nil
+	}
+
+	bound, err := client.WithTools(mgr.ToolInfos())
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind MCP tools to Anthropic client: %w", err)
+	}
+	return bound, nil
+}
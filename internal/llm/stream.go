@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Chunk is a single token-level piece of a streamed response, along with
+// the running usage totals known so far.
+type Chunk struct {
+	Content string
+	Usage   Usage
+}
+
+// StreamingClient wraps a model.ChatModel for token-level streaming. Both
+// the OpenAI (SSE) and Ollama (NDJSON) eino-ext backends implement
+// model.ChatModel's Stream method, so this works unmodified across
+// providers; it just drains eino's *schema.StreamReader into a plain Go
+// channel so callers don't need to import eino's schema package.
+type StreamingClient struct {
+	model model.ChatModel
+}
+
+// NewStreamingClient wraps chatModel for streaming use.
+func NewStreamingClient(chatModel model.ChatModel) *StreamingClient {
+	return &StreamingClient{model: chatModel}
+}
+
+// Stream starts a streaming generation and returns a channel of Chunks. The
+// channel closes when generation completes, ctx is canceled, or an error
+// occurs partway through (in which case the chunks received so far are
+// still delivered before the channel closes). Canceling ctx closes the
+// underlying stream reader, which in turn closes the HTTP response body so
+// the provider stops generating server-side instead of just being ignored
+// client-side.
+func (c *StreamingClient) Stream(ctx context.Context, messages []*schema.Message) (<-chan Chunk, error) {
+	reader, err := c.model.Stream(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start streaming generation: %w", err)
+	}
+
+	chunks := make(chan Chunk)
+	go func() {
+		defer close(chunks)
+		defer reader.Close()
+
+		var usage Usage
+		for {
+			msg, err := reader.Recv()
+			if err != nil {
+				return // io.EOF on a clean finish, ctx.Err() after cancellation
+			}
+
+			if u := UsageFromMessage(msg); u.PromptTokens > 0 || u.CompletionTokens > 0 {
+				usage = u
+			} else {
+				usage.CompletionTokens++
+			}
+
+			select {
+			case chunks <- Chunk{Content: msg.Content, Usage: usage}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chunks, nil
+}
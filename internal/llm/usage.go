@@ -0,0 +1,30 @@
+package llm
+
+import "github.com/cloudwego/eino/schema"
+
+// Usage captures token accounting for a single LLM call, normalized across
+// providers so callers get real numbers instead of a single opaque token
+// count.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// TotalTokens returns the combined prompt and completion token count.
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// UsageFromMessage extracts token usage from an eino response message.
+// Returns a zero Usage if the message carries no usage metadata.
+func UsageFromMessage(msg *schema.Message) Usage {
+	if msg == nil || msg.ResponseMeta == nil || msg.ResponseMeta.Usage == nil {
+		return Usage{}
+	}
+
+	usage := msg.ResponseMeta.Usage
+	return Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	}
+}
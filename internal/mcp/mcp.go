@@ -0,0 +1,361 @@
+// Package mcp is WorkWise's client-side bridge to external Model Context
+// Protocol servers. It owns the transport/JSON-RPC plumbing needed to
+// discover and invoke tools exposed by MCP servers, and translates them into
+// eino tool definitions so an agent's LLM can call them directly.
+//
+// This package is independent from pkg/mcp, which is a separate MCP client
+// built around its Server interface (StdioServer, SSEServer); the agent and
+// LLM layers use this package, not that one.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Tool describes a tool advertised by an MCP server via tools/list.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Client is a minimal JSON-RPC 2.0 client for a single MCP server, speaking
+// either the stdio transport (a spawned subprocess) or plain HTTP POST.
+// target selects the transport: a "http://" or "https://" URL uses HTTP,
+// anything else is treated as a shell command to launch over stdio.
+type Client struct {
+	target string
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	scanner *bufio.Scanner
+	httpURL string
+
+	nextID int64
+	tools  []Tool
+}
+
+// NewClient creates a client for the given server target.
+func NewClient(target string) *Client {
+	return &Client{target: target}
+}
+
+// Connect starts the subprocess (stdio transport) or records the endpoint
+// (HTTP transport), then performs the MCP initialize handshake.
+func (c *Client) Connect(ctx context.Context) error {
+	if strings.HasPrefix(c.target, "http://") || strings.HasPrefix(c.target, "https://") {
+		c.httpURL = c.target
+	} else {
+		parts := strings.Fields(c.target)
+		if len(parts) == 0 {
+			return fmt.Errorf("mcp: empty server command")
+		}
+
+		cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("mcp: failed to open stdin for %q: %w", c.target, err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("mcp: failed to open stdout for %q: %w", c.target, err)
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("mcp: failed to start server %q: %w", c.target, err)
+		}
+
+		c.cmd = cmd
+		c.stdin = stdin
+		c.scanner = bufio.NewScanner(stdout)
+		c.scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	}
+
+	if _, err := c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": "2024-11-05",
+		"clientInfo":      map[string]string{"name": "workwise", "version": "dev"},
+	}); err != nil {
+		return fmt.Errorf("mcp: initialize handshake with %q failed: %w", c.target, err)
+	}
+
+	return c.notify(ctx, "initialized", nil)
+}
+
+// Disconnect tears down the transport, killing the child process if one was
+// spawned for the stdio transport.
+func (c *Client) Disconnect(_ context.Context) error {
+	if c.cmd == nil || c.cmd.Process == nil {
+		return nil
+	}
+	return c.cmd.Process.Kill()
+}
+
+// ListTools discovers the tools exposed by the server and caches them on
+// the client, where Manager.ToolInfos reads them.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	result, err := c.call(ctx, "tools/list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: tools/list on %q failed: %w", c.target, err)
+	}
+
+	var payload struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(result, &payload); err != nil {
+		return nil, fmt.Errorf("mcp: failed to decode tools/list result: %w", err)
+	}
+
+	c.tools = payload.Tools
+	return c.tools, nil
+}
+
+// CallTool invokes a named tool with the given JSON arguments and returns
+// its concatenated text content.
+func (c *Client) CallTool(ctx context.Context, name string, args json.RawMessage) (string, error) {
+	result, err := c.call(ctx, "tools/call", map[string]any{
+		"name":      name,
+		"arguments": json.RawMessage(args),
+	})
+	if err != nil {
+		return "", fmt.Errorf("mcp: tools/call %q on %q failed: %w", name, c.target, err)
+	}
+
+	var payload struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(result, &payload); err != nil {
+		return "", fmt.Errorf("mcp: failed to decode tools/call result: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, block := range payload.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	id := atomic.AddInt64(&c.nextID, 1)
+	req := rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}
+
+	var resp rpcResponse
+	var err error
+	if c.httpURL != "" {
+		resp, err = c.sendHTTP(ctx, req)
+	} else {
+		resp, err = c.sendStdio(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("server error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+	return resp.Result, nil
+}
+
+// notify sends a JSON-RPC notification (no response expected).
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	req := rpcRequest{JSONRPC: "2.0", Method: method, Params: params}
+
+	if c.httpURL != "" {
+		_, err := c.sendHTTP(ctx, req)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = c.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// sendStdio writes req and reads frames off the server's stdout until it
+// finds the response whose id matches req.ID, skipping any other frame
+// (e.g. a notifications/... frame, which an MCP server may emit at any
+// time and which carries no id) instead of treating it as the result.
+func (c *Client) sendStdio(req rpcRequest) (rpcResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return rpcResponse{}, err
+	}
+	if _, err := c.stdin.Write(append(data, '\n')); err != nil {
+		return rpcResponse{}, fmt.Errorf("mcp: failed to write request: %w", err)
+	}
+
+	for {
+		if !c.scanner.Scan() {
+			if err := c.scanner.Err(); err != nil {
+				return rpcResponse{}, fmt.Errorf("mcp: failed to read response: %w", err)
+			}
+			return rpcResponse{}, fmt.Errorf("mcp: server closed stdout")
+		}
+		line := c.scanner.Bytes()
+
+		var probe struct {
+			ID *int64 `json:"id"`
+		}
+		if err := json.Unmarshal(line, &probe); err != nil {
+			return rpcResponse{}, fmt.Errorf("mcp: failed to decode response: %w", err)
+		}
+		if probe.ID == nil || *probe.ID != req.ID {
+			// Not our response: a notification, or (if multiple calls were
+			// ever in flight) another request's reply. Keep reading.
+			continue
+		}
+
+		var resp rpcResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return rpcResponse{}, fmt.Errorf("mcp: failed to decode response: %w", err)
+		}
+		return resp, nil
+	}
+}
+
+func (c *Client) sendHTTP(ctx context.Context, req rpcRequest) (rpcResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return rpcResponse{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.httpURL, bytes.NewReader(data))
+	if err != nil {
+		return rpcResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return rpcResponse{}, fmt.Errorf("mcp: request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	var resp rpcResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return rpcResponse{}, fmt.Errorf("mcp: failed to decode response: %w", err)
+	}
+	return resp, nil
+}
+
+// Manager owns one Client per configured MCP server and exposes the
+// aggregate tool surface to the agent/LLM layers.
+type Manager struct {
+	clients map[string]*Client
+	owner   map[string]string // tool name -> owning server target
+}
+
+// NewManager creates an empty Manager.
+func NewManager() *Manager {
+	return &Manager{
+		clients: make(map[string]*Client),
+		owner:   make(map[string]string),
+	}
+}
+
+// Connect launches/contacts every server target and discovers its tools.
+func Connect(ctx context.Context, servers []string) (*Manager, error) {
+	m := NewManager()
+	for _, target := range servers {
+		client := NewClient(target)
+		if err := client.Connect(ctx); err != nil {
+			return nil, fmt.Errorf("mcp: connecting to %q: %w", target, err)
+		}
+
+		tools, err := client.ListTools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("mcp: listing tools from %q: %w", target, err)
+		}
+
+		m.clients[target] = client
+		for _, tool := range tools {
+			m.owner[tool.Name] = target
+		}
+	}
+	return m, nil
+}
+
+// ToolInfos translates every discovered MCP tool into an eino ToolInfo so it
+// can be bound to a model.ChatModel that supports tool calling.
+func (m *Manager) ToolInfos() []*schema.ToolInfo {
+	infos := make([]*schema.ToolInfo, 0, len(m.owner))
+	for name, target := range m.owner {
+		for _, t := range m.clients[target].tools {
+			if t.Name != name {
+				continue
+			}
+			infos = append(infos, &schema.ToolInfo{
+				Name:        t.Name,
+				Desc:        t.Description,
+				ParamsOneOf: schema.NewParamsOneOfByJSONSchema(t.InputSchema),
+			})
+		}
+	}
+	return infos
+}
+
+// CallTool routes a tool invocation to whichever server advertised it.
+func (m *Manager) CallTool(ctx context.Context, name string, args json.RawMessage) (string, error) {
+	target, ok := m.owner[name]
+	if !ok {
+		return "", fmt.Errorf("mcp: no connected server exposes tool %q", name)
+	}
+	return m.clients[target].CallTool(ctx, name, args)
+}
+
+// HasTools reports whether any server has advertised at least one tool.
+func (m *Manager) HasTools() bool {
+	return len(m.owner) > 0
+}
+
+// Close disconnects every managed server.
+func (m *Manager) Close(ctx context.Context) error {
+	var firstErr error
+	for _, client := range m.clients {
+		if err := client.Disconnect(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -1,47 +1,158 @@
+// Package mcp implements a client for the Model Context Protocol (MCP), the
+// open JSON-RPC 2.0 protocol Anthropic defined for connecting AI
+// applications to external tool/resource/prompt servers. Server is the
+// client-side handle to one such server; StdioServer and SSEServer are the
+// two concrete transports this package ships.
 package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 )
 
-// Server represents an MCP (Model Context Protocol) server
-// This is a placeholder for future Anthropic MCP integration
+// Server is a client-side connection to a single MCP server, speaking
+// whatever transport (stdio, SSE, ...) the concrete implementation uses.
 type Server interface {
-	// Connect establishes connection to the MCP server
+	// Connect performs the transport-specific handshake (spawning a
+	// subprocess or opening an HTTP/SSE stream) followed by the MCP
+	// initialize/initialized exchange.
 	Connect(ctx context.Context) error
 
-	// Disconnect closes connection to the MCP server
+	// Disconnect tears down the transport.
 	Disconnect(ctx context.Context) error
 
-	// ListTools lists available tools from the MCP server
+	// ListTools returns the tools this server advertises.
 	ListTools(ctx context.Context) ([]Tool, error)
 
-	// CallTool invokes a tool on the MCP server
-	CallTool(ctx context.Context, name string, params map[string]interface{}) (interface{}, error)
+	// CallTool invokes a tool by name, returning its structured content
+	// blocks (text, image, and/or resource).
+	CallTool(ctx context.Context, name string, params map[string]interface{}) (*CallToolResult, error)
+
+	// ListResources returns the resources this server advertises.
+	ListResources(ctx context.Context) ([]Resource, error)
+
+	// ReadResource fetches the contents of a single resource by URI.
+	ReadResource(ctx context.Context, uri string) ([]ResourceContent, error)
+
+	// ListPrompts returns the prompt templates this server advertises.
+	ListPrompts(ctx context.Context) ([]Prompt, error)
 }
 
-// Tool represents an MCP tool
+// JSONSchema is a minimal JSON Schema representation used to describe the
+// shape of a Tool's input or a PromptArgument.
+type JSONSchema struct {
+	Type        string                 `json:"type,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Enum        []interface{}          `json:"enum,omitempty"`
+}
+
+// Tool represents an MCP tool advertised via tools/list.
 type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Parameters  map[string]interface{} `json:"parameters"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	InputSchema JSONSchema `json:"inputSchema"`
+}
+
+// ContentBlock is a single piece of a tools/call result: text, an
+// inline-base64 image, or an embedded resource.
+type ContentBlock struct {
+	Type     string           `json:"type"`
+	Text     string           `json:"text,omitempty"`
+	Data     string           `json:"data,omitempty"`
+	MimeType string           `json:"mimeType,omitempty"`
+	Resource *ResourceContent `json:"resource,omitempty"`
+}
+
+// CallToolRequest is the params object sent with a tools/call request.
+type CallToolRequest struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// CallToolResult is a tools/call response: a list of content blocks, with
+// IsError set if the tool itself reported failure (as opposed to the
+// JSON-RPC call failing outright).
+type CallToolResult struct {
+	Content []ContentBlock `json:"content"`
+	IsError bool           `json:"isError,omitempty"`
+}
+
+// Resource describes a single resource advertised via resources/list.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContent is the body of a resource returned by resources/read:
+// exactly one of Text or Blob (base64) is populated, depending on MimeType.
+type ResourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// PromptArgument describes a single named input a Prompt template accepts.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Prompt describes a prompt template advertised via prompts/list.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// Notification is a server-pushed JSON-RPC message with no id, such as
+// notifications/tools/list_changed.
+type Notification struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
 }
 
-// Manager manages multiple MCP servers
+// notifier is implemented by transports that expose a stream of
+// server-pushed notifications. Manager uses it to invalidate its tool
+// cache without every Server implementation being required to support it.
+type notifier interface {
+	Notifications() <-chan Notification
+}
+
+// Manager manages multiple MCP servers and caches their tool lists,
+// invalidating the cache for a server when it pushes a
+// notifications/tools/list_changed notification.
 type Manager struct {
+	mu      sync.RWMutex
 	servers map[string]Server
+	cancel  map[string]context.CancelFunc
+
+	toolCacheMu sync.RWMutex
+	toolCache   map[string][]Tool
 }
 
-// NewManager creates a new MCP manager
+// NewManager creates a new MCP manager.
 func NewManager() *Manager {
 	return &Manager{
-		servers: make(map[string]Server),
+		servers:   make(map[string]Server),
+		cancel:    make(map[string]context.CancelFunc),
+		toolCache: make(map[string][]Tool),
 	}
 }
 
-// RegisterServer registers an MCP server
+// RegisterServer registers an MCP server under name.
 func (m *Manager) RegisterServer(name string, server Server) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if _, exists := m.servers[name]; exists {
 		return fmt.Errorf("server %s already registered", name)
 	}
@@ -49,8 +160,11 @@ func (m *Manager) RegisterServer(name string, server Server) error {
 	return nil
 }
 
-// GetServer retrieves a registered MCP server
+// GetServer retrieves a registered MCP server.
 func (m *Manager) GetServer(name string) (Server, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	server, exists := m.servers[name]
 	if !exists {
 		return nil, fmt.Errorf("server %s not found", name)
@@ -58,8 +172,11 @@ func (m *Manager) GetServer(name string) (Server, error) {
 	return server, nil
 }
 
-// ListServers returns all registered server names
+// ListServers returns all registered server names.
 func (m *Manager) ListServers() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	names := make([]string, 0, len(m.servers))
 	for name := range m.servers {
 		names = append(names, name)
@@ -67,20 +184,41 @@ func (m *Manager) ListServers() []string {
 	return names
 }
 
-// ConnectAll connects to all registered servers
+// ConnectAll connects to every registered server and, for transports that
+// support it, starts a background goroutine watching for notifications
+// that should invalidate the tool cache.
 func (m *Manager) ConnectAll(ctx context.Context) error {
+	m.mu.RLock()
+	servers := make(map[string]Server, len(m.servers))
 	for name, server := range m.servers {
+		servers[name] = server
+	}
+	m.mu.RUnlock()
+
+	for name, server := range servers {
 		if err := server.Connect(ctx); err != nil {
 			return fmt.Errorf("failed to connect to server %s: %w", name, err)
 		}
+		m.watchNotifications(name, server)
 	}
 	return nil
 }
 
-// DisconnectAll disconnects from all registered servers
+// DisconnectAll disconnects from every registered server and stops any
+// notification watchers started for it.
 func (m *Manager) DisconnectAll(ctx context.Context) error {
+	m.mu.RLock()
+	servers := make(map[string]Server, len(m.servers))
+	for name, server := range m.servers {
+		servers[name] = server
+	}
+	m.mu.RUnlock()
+
 	var lastErr error
-	for _, server := range m.servers {
+	for name, server := range servers {
+		if cancel, ok := m.cancel[name]; ok {
+			cancel()
+		}
 		if err := server.Disconnect(ctx); err != nil {
 			lastErr = err
 		}
@@ -88,5 +226,62 @@ func (m *Manager) DisconnectAll(ctx context.Context) error {
 	return lastErr
 }
 
-// Note: This is a foundational structure for future MCP support.
-// Actual implementation will be added when integrating with Anthropic's MCP specification.
+// ListTools returns name's tool list, reusing a cached copy from the last
+// call unless the server has since pushed a list_changed notification.
+func (m *Manager) ListTools(ctx context.Context, name string) ([]Tool, error) {
+	m.toolCacheMu.RLock()
+	cached, ok := m.toolCache[name]
+	m.toolCacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	server, err := m.GetServer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := server.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.toolCacheMu.Lock()
+	m.toolCache[name] = tools
+	m.toolCacheMu.Unlock()
+
+	return tools, nil
+}
+
+// watchNotifications invalidates name's cached tool list whenever its
+// server pushes a notifications/tools/list_changed message. It's a no-op
+// for Server implementations that don't expose a notification stream.
+func (m *Manager) watchNotifications(name string, server Server) {
+	n, ok := server.(notifier)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	m.cancel[name] = cancel
+	m.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case note, ok := <-n.Notifications():
+				if !ok {
+					return
+				}
+				if note.Method == "notifications/tools/list_changed" {
+					m.toolCacheMu.Lock()
+					delete(m.toolCache, name)
+					m.toolCacheMu.Unlock()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
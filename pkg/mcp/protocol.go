@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// transport is the minimal request/notify surface every concrete Server
+// implementation provides. The MCP method helpers below are implemented
+// once against this interface instead of being duplicated per transport.
+type transport interface {
+	call(ctx context.Context, method string, params interface{}) (json.RawMessage, error)
+	notify(method string, params interface{}) error
+}
+
+// handshake performs the MCP initialize -> initialized exchange common to
+// every transport.
+func handshake(ctx context.Context, t transport) error {
+	if _, err := t.call(ctx, "initialize", map[string]any{
+		"protocolVersion": "2024-11-05",
+		"clientInfo":      map[string]string{"name": "workwise", "version": "dev"},
+	}); err != nil {
+		return fmt.Errorf("mcp: initialize handshake failed: %w", err)
+	}
+	return t.notify("initialized", nil)
+}
+
+// listTools implements Server.ListTools against any transport.
+func listTools(ctx context.Context, t transport) ([]Tool, error) {
+	result, err := t.call(ctx, "tools/list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: tools/list failed: %w", err)
+	}
+
+	var payload struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(result, &payload); err != nil {
+		return nil, fmt.Errorf("mcp: failed to decode tools/list result: %w", err)
+	}
+	return payload.Tools, nil
+}
+
+// callTool implements Server.CallTool against any transport.
+func callTool(ctx context.Context, t transport, name string, params map[string]interface{}) (*CallToolResult, error) {
+	args, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: failed to marshal arguments for %q: %w", name, err)
+	}
+
+	result, err := t.call(ctx, "tools/call", CallToolRequest{Name: name, Arguments: args})
+	if err != nil {
+		return nil, fmt.Errorf("mcp: tools/call %q failed: %w", name, err)
+	}
+
+	var callResult CallToolResult
+	if err := json.Unmarshal(result, &callResult); err != nil {
+		return nil, fmt.Errorf("mcp: failed to decode tools/call result: %w", err)
+	}
+	return &callResult, nil
+}
+
+// listResources implements Server.ListResources against any transport.
+func listResources(ctx context.Context, t transport) ([]Resource, error) {
+	result, err := t.call(ctx, "resources/list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: resources/list failed: %w", err)
+	}
+
+	var payload struct {
+		Resources []Resource `json:"resources"`
+	}
+	if err := json.Unmarshal(result, &payload); err != nil {
+		return nil, fmt.Errorf("mcp: failed to decode resources/list result: %w", err)
+	}
+	return payload.Resources, nil
+}
+
+// readResource implements Server.ReadResource against any transport.
+func readResource(ctx context.Context, t transport, uri string) ([]ResourceContent, error) {
+	result, err := t.call(ctx, "resources/read", map[string]string{"uri": uri})
+	if err != nil {
+		return nil, fmt.Errorf("mcp: resources/read %q failed: %w", uri, err)
+	}
+
+	var payload struct {
+		Contents []ResourceContent `json:"contents"`
+	}
+	if err := json.Unmarshal(result, &payload); err != nil {
+		return nil, fmt.Errorf("mcp: failed to decode resources/read result: %w", err)
+	}
+	return payload.Contents, nil
+}
+
+// listPrompts implements Server.ListPrompts against any transport.
+func listPrompts(ctx context.Context, t transport) ([]Prompt, error) {
+	result, err := t.call(ctx, "prompts/list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: prompts/list failed: %w", err)
+	}
+
+	var payload struct {
+		Prompts []Prompt `json:"prompts"`
+	}
+	if err := json.Unmarshal(result, &payload); err != nil {
+		return nil, fmt.Errorf("mcp: failed to decode prompts/list result: %w", err)
+	}
+	return payload.Prompts, nil
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request or notification envelope. A
+// notification omits ID (its zero value is never sent by newRequest,
+// which always assigns a nonzero ID).
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response envelope.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// pendingCall is a single in-flight request awaiting its response.
+type pendingCall struct {
+	result chan rpcResponse
+}
+
+// rpcCorrelator assigns request IDs and correlates incoming responses back
+// to their caller. It's shared by every transport (StdioServer, SSEServer)
+// so request-ID bookkeeping, context cancellation, and notification
+// dispatch aren't reimplemented per transport.
+type rpcCorrelator struct {
+	nextID int64
+
+	mu      sync.Mutex
+	pending map[int64]pendingCall
+
+	notifications chan Notification
+}
+
+// newRPCCorrelator creates a correlator ready to track pending calls.
+func newRPCCorrelator() *rpcCorrelator {
+	return &rpcCorrelator{
+		pending:       make(map[int64]pendingCall),
+		notifications: make(chan Notification, 16),
+	}
+}
+
+// newRequest allocates a fresh request ID and registers a pending call for
+// it, returning the request envelope to send and a channel its response
+// will arrive on.
+func (c *rpcCorrelator) newRequest(method string, params interface{}) (rpcRequest, chan rpcResponse) {
+	id := atomic.AddInt64(&c.nextID, 1)
+	result := make(chan rpcResponse, 1)
+
+	c.mu.Lock()
+	c.pending[id] = pendingCall{result: result}
+	c.mu.Unlock()
+
+	return rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}, result
+}
+
+// await blocks until id's response arrives or ctx is cancelled, whichever
+// comes first, always cleaning up the pending map entry.
+func (c *rpcCorrelator) await(ctx context.Context, id int64, result chan rpcResponse) (json.RawMessage, error) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	select {
+	case resp := <-result:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("server error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch routes a single decoded incoming frame: frames with an id are
+// delivered to their waiting caller, frames without one are treated as
+// notifications.
+func (c *rpcCorrelator) dispatch(raw json.RawMessage) {
+	var probe struct {
+		ID *int64 `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return
+	}
+
+	if probe.ID == nil {
+		var note Notification
+		if err := json.Unmarshal(raw, &note); err == nil {
+			select {
+			case c.notifications <- note:
+			default:
+				// Notifications are best-effort cache-invalidation hints,
+				// not guaranteed delivery; drop rather than block the
+				// reader loop if nobody's listening yet.
+			}
+		}
+		return
+	}
+
+	var resp rpcResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	call, ok := c.pending[resp.ID]
+	c.mu.Unlock()
+	if ok {
+		call.result <- resp
+	}
+}
+
+// Notifications returns the channel of incoming server-pushed
+// notifications, e.g. notifications/tools/list_changed.
+func (c *rpcCorrelator) Notifications() <-chan Notification {
+	return c.notifications
+}
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// SSEServer is a Server that POSTs JSON-RPC requests over HTTP and
+// receives responses/notifications asynchronously over a text/event-stream
+// connection, the transport MCP uses for remote/hosted tool servers.
+type SSEServer struct {
+	streamURL string
+
+	*rpcCorrelator
+
+	httpClient *http.Client
+	cancel     context.CancelFunc
+
+	mu      sync.Mutex
+	postURL string
+}
+
+// NewSSEServer creates an SSEServer that opens its event stream at
+// streamURL. The URL servers POST against may differ (advertised via an
+// "endpoint" SSE event); until one arrives, requests are posted to
+// streamURL itself.
+func NewSSEServer(streamURL string) *SSEServer {
+	return &SSEServer{
+		streamURL:     streamURL,
+		rpcCorrelator: newRPCCorrelator(),
+		httpClient:    http.DefaultClient,
+	}
+}
+
+// Connect opens the SSE stream and performs the MCP handshake.
+func (s *SSEServer) Connect(ctx context.Context) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, s.streamURL, nil)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("mcp: failed to build SSE request for %q: %w", s.streamURL, err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("mcp: failed to open SSE stream to %q: %w", s.streamURL, err)
+	}
+
+	s.mu.Lock()
+	s.postURL = s.streamURL
+	s.mu.Unlock()
+	s.cancel = cancel
+
+	go s.readLoop(resp.Body)
+
+	return handshake(ctx, s)
+}
+
+// Disconnect closes the SSE stream.
+func (s *SSEServer) Disconnect(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func (s *SSEServer) readLoop(body io.ReadCloser) {
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			s.handleEvent(event, data)
+		case line == "":
+			event = ""
+		}
+	}
+}
+
+func (s *SSEServer) handleEvent(event, data string) {
+	switch event {
+	case "endpoint":
+		s.mu.Lock()
+		s.postURL = s.resolvePostURL(data)
+		s.mu.Unlock()
+	default:
+		s.dispatch(json.RawMessage(data))
+	}
+}
+
+func (s *SSEServer) resolvePostURL(endpoint string) string {
+	base, err := url.Parse(s.streamURL)
+	if err != nil {
+		return endpoint
+	}
+	ref, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func (s *SSEServer) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	req, result := s.newRequest(method, params)
+	if err := s.post(ctx, req); err != nil {
+		return nil, err
+	}
+	return s.await(ctx, req.ID, result)
+}
+
+func (s *SSEServer) notify(method string, params interface{}) error {
+	return s.post(context.Background(), rpcRequest{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *SSEServer) post(ctx context.Context, req rpcRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("mcp: failed to marshal request: %w", err)
+	}
+
+	s.mu.Lock()
+	postURL := s.postURL
+	s.mu.Unlock()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("mcp: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("mcp: request to %q failed: %w", postURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("mcp: server at %q returned status %d", postURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// ListTools implements Server.
+func (s *SSEServer) ListTools(ctx context.Context) ([]Tool, error) {
+	return listTools(ctx, s)
+}
+
+// CallTool implements Server.
+func (s *SSEServer) CallTool(ctx context.Context, name string, params map[string]interface{}) (*CallToolResult, error) {
+	return callTool(ctx, s, name, params)
+}
+
+// ListResources implements Server.
+func (s *SSEServer) ListResources(ctx context.Context) ([]Resource, error) {
+	return listResources(ctx, s)
+}
+
+// ReadResource implements Server.
+func (s *SSEServer) ReadResource(ctx context.Context, uri string) ([]ResourceContent, error) {
+	return readResource(ctx, s, uri)
+}
+
+// ListPrompts implements Server.
+func (s *SSEServer) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	return listPrompts(ctx, s)
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// StdioServer is a Server that spawns a child process and speaks
+// newline-delimited JSON-RPC 2.0 over its stdin/stdout, the transport MCP
+// uses for locally-installed tool servers.
+type StdioServer struct {
+	command string
+	args    []string
+
+	*rpcCorrelator
+
+	cmd *exec.Cmd
+
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+}
+
+// NewStdioServer creates a StdioServer that launches command with args
+// when Connect is called.
+func NewStdioServer(command string, args ...string) *StdioServer {
+	return &StdioServer{
+		command:       command,
+		args:          args,
+		rpcCorrelator: newRPCCorrelator(),
+	}
+}
+
+// Connect starts the child process and performs the MCP handshake.
+func (s *StdioServer) Connect(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, s.command, s.args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("mcp: failed to open stdin for %q: %w", s.command, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("mcp: failed to open stdout for %q: %w", s.command, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("mcp: failed to start server %q: %w", s.command, err)
+	}
+
+	s.cmd = cmd
+	s.stdin = stdin
+
+	go s.readLoop(stdout)
+
+	return handshake(ctx, s)
+}
+
+// Disconnect kills the child process.
+func (s *StdioServer) Disconnect(_ context.Context) error {
+	if s.cmd == nil || s.cmd.Process == nil {
+		return nil
+	}
+	return s.cmd.Process.Kill()
+}
+
+func (s *StdioServer) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		s.dispatch(json.RawMessage(line))
+	}
+}
+
+func (s *StdioServer) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	req, result := s.newRequest(method, params)
+	if err := s.write(req); err != nil {
+		return nil, err
+	}
+	return s.await(ctx, req.ID, result)
+}
+
+func (s *StdioServer) notify(method string, params interface{}) error {
+	return s.write(rpcRequest{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *StdioServer) write(req rpcRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("mcp: failed to marshal request: %w", err)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := s.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("mcp: failed to write request: %w", err)
+	}
+	return nil
+}
+
+// ListTools implements Server.
+func (s *StdioServer) ListTools(ctx context.Context) ([]Tool, error) {
+	return listTools(ctx, s)
+}
+
+// CallTool implements Server.
+func (s *StdioServer) CallTool(ctx context.Context, name string, params map[string]interface{}) (*CallToolResult, error) {
+	return callTool(ctx, s, name, params)
+}
+
+// ListResources implements Server.
+func (s *StdioServer) ListResources(ctx context.Context) ([]Resource, error) {
+	return listResources(ctx, s)
+}
+
+// ReadResource implements Server.
+func (s *StdioServer) ReadResource(ctx context.Context, uri string) ([]ResourceContent, error) {
+	return readResource(ctx, s, uri)
+}
+
+// ListPrompts implements Server.
+func (s *StdioServer) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	return listPrompts(ctx, s)
+}
@@ -0,0 +1,293 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/warm3snow/WorkWise/internal/analytics"
+)
+
+// DefaultExecTimeout bounds how long a skill script or tool invocation may
+// run when ctx carries no deadline of its own.
+const DefaultExecTimeout = 2 * time.Minute
+
+// sandboxWrappers are checked, in order, for a Linux sandboxing binary to
+// wrap invocations in for filesystem/network isolation. The first one found
+// on PATH is used; if neither is present, commands run unwrapped.
+var sandboxWrappers = []string{"bwrap", "nsjail"}
+
+// ExecResult is the outcome of an Executor-run script or tool invocation.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// toolGrant is one parsed entry from a Skill's AllowedTools list, e.g.
+// "Bash(git:*)" becomes {Tool: "Bash", Binary: "git", Pattern: "*"}, and a
+// bare "WebFetch" becomes {Tool: "WebFetch"}.
+type toolGrant struct {
+	Tool    string
+	Binary  string
+	Pattern string
+}
+
+// parseAllowedTools parses a Skill's AllowedTools frontmatter entries into
+// toolGrants. An entry that doesn't parse is skipped rather than erroring,
+// since a malformed grant should deny capability, not block every other one.
+func parseAllowedTools(entries []string) []toolGrant {
+	grants := make([]toolGrant, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		open := strings.Index(entry, "(")
+		if open == -1 {
+			grants = append(grants, toolGrant{Tool: entry})
+			continue
+		}
+		if !strings.HasSuffix(entry, ")") {
+			continue
+		}
+
+		tool := strings.TrimSpace(entry[:open])
+		inner := entry[open+1 : len(entry)-1]
+		binary, pattern := inner, "*"
+		if idx := strings.Index(inner, ":"); idx != -1 {
+			binary, pattern = inner[:idx], inner[idx+1:]
+		}
+		grants = append(grants, toolGrant{Tool: tool, Binary: binary, Pattern: pattern})
+	}
+	return grants
+}
+
+// toolGranted reports whether grants permits running binary on behalf of
+// tool, matching args[0] (or "" if there are none) against the grant's glob
+// pattern.
+func toolGranted(grants []toolGrant, tool, binary string, args []string) bool {
+	probe := ""
+	if len(args) > 0 {
+		probe = args[0]
+	}
+	for _, g := range grants {
+		if g.Tool != tool || g.Binary != binary {
+			continue
+		}
+		if ok, err := filepath.Match(g.Pattern, probe); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Executor runs a Skill's own scripts, and the external tools its
+// AllowedTools grants permit, under a restricted PATH, a working directory
+// pinned to the skill's folder, and a context timeout. Every invocation is
+// recorded through an analytics.Tracker as a BehaviorSkillExec event, so
+// skill usage shows up in productivity stats alongside tool calls.
+type Executor struct {
+	// ProxyAddr, when set, is handed to scripts via the
+	// WORKWISE_HTTP_PROXY env var whenever the skill's AllowedTools grants
+	// WebFetch, so outbound HTTP goes through a single auditable egress
+	// point instead of unrestricted network access. Empty leaves WebFetch
+	// grants without an env var (the script is on its own for egress).
+	ProxyAddr string
+
+	// Timeout bounds each invocation when ctx carries no deadline of its own.
+	// Defaults to DefaultExecTimeout.
+	Timeout time.Duration
+
+	tracker *analytics.Tracker
+}
+
+// NewExecutor creates an Executor that records invocations through tracker.
+// A nil tracker is fine; invocations simply aren't recorded.
+func NewExecutor(tracker *analytics.Tracker) *Executor {
+	return &Executor{tracker: tracker}
+}
+
+// Run executes skill's scriptName under a restricted environment: PATH
+// limited to the binaries skill.AllowedTools grants, working directory
+// pinned to the skill's folder, stdin/stdout/stderr piped, and a deadline
+// from ctx (or e.Timeout/DefaultExecTimeout if ctx has none). On Linux it's
+// wrapped in bwrap or nsjail when either is present on PATH.
+func (e *Executor) Run(ctx context.Context, skill *Skill, scriptName string, args []string, sessionID string) (*ExecResult, error) {
+	if !skill.HasScript(scriptName) {
+		return nil, fmt.Errorf("skill %q has no script %q", skill.Name, scriptName)
+	}
+	return e.run(ctx, skill, skill.GetScriptPath(scriptName), scriptName, args, sessionID)
+}
+
+// RunTool executes binary (e.g. "git") on skill's behalf, refusing unless
+// skill.AllowedTools grants tool for binary and args[0] matches that
+// grant's pattern. It runs under the same restricted environment as Run.
+func (e *Executor) RunTool(ctx context.Context, skill *Skill, tool, binary string, args []string, sessionID string) (*ExecResult, error) {
+	grants := parseAllowedTools(skill.AllowedTools)
+	if !toolGranted(grants, tool, binary, args) {
+		return nil, fmt.Errorf("skill %q does not grant %s to run %q", skill.Name, tool, binary)
+	}
+
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("tool %q not found on PATH: %w", binary, err)
+	}
+	return e.run(ctx, skill, path, binary, args, sessionID)
+}
+
+// run is the shared implementation behind Run and RunTool: it builds a
+// restricted environment, pins the working directory to skill's folder,
+// enforces a timeout, optionally wraps the command in a Linux sandbox, and
+// records the outcome through e.tracker. label identifies the invocation in
+// tracked events (the script name for Run, the binary name for RunTool).
+func (e *Executor) run(ctx context.Context, skill *Skill, command, label string, args []string, sessionID string) (*ExecResult, error) {
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	grants := parseAllowedTools(skill.AllowedTools)
+
+	env, cleanup := e.restrictedEnv(grants)
+	defer cleanup()
+
+	name, fullArgs := wrapForSandbox(command, args, hasWebFetchGrant(grants))
+	cmd := exec.CommandContext(ctx, name, fullArgs...)
+	cmd.Dir = skill.SkillPath
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+
+	result := &ExecResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: exitCode,
+		Duration: duration,
+	}
+
+	if e.tracker != nil {
+		e.tracker.TrackSkillExec(skill.Name, label, duration, exitCode == 0, sessionID)
+	}
+
+	// cmd.ProcessState is nil only when the process never started (e.g. the
+	// sandbox wrapper or command itself couldn't be exec'd); a non-zero
+	// exit from a process that did run is reported via ExitCode, not err.
+	if cmd.ProcessState == nil && err != nil {
+		return result, fmt.Errorf("failed to run %q for skill %q: %w", command, skill.Name, err)
+	}
+	return result, nil
+}
+
+// restrictedEnv builds the environment passed to a skill invocation: PATH
+// stripped to a temp directory of symlinks for exactly the binaries grants
+// permits (so a script can't shell out to anything ungranted even if it
+// bypasses RunTool's validation), plus WORKWISE_HTTP_PROXY when WebFetch is
+// granted. The returned cleanup func removes the temp PATH directory and
+// must be called once the invocation finishes.
+func (e *Executor) restrictedEnv(grants []toolGrant) (env []string, cleanup func()) {
+	pathDir, cleanup := whitelistedPathDir(grants)
+	env = []string{
+		"HOME=" + os.Getenv("HOME"),
+		"PATH=" + pathDir,
+	}
+
+	if hasWebFetchGrant(grants) && e.ProxyAddr != "" {
+		env = append(env, "WORKWISE_HTTP_PROXY="+e.ProxyAddr)
+	}
+
+	return env, cleanup
+}
+
+// hasWebFetchGrant reports whether grants permits WebFetch.
+func hasWebFetchGrant(grants []toolGrant) bool {
+	for _, g := range grants {
+		if g.Tool == "WebFetch" {
+			return true
+		}
+	}
+	return false
+}
+
+// whitelistedPathDir creates a temp directory containing one symlink per
+// binary grants permits (e.g. Bash(git:*) permits "git"), so it can be used
+// as the sole PATH entry for a restricted invocation. Binaries that can't
+// be resolved on the host PATH are silently skipped, leaving that grant
+// unusable rather than failing the whole invocation. The returned cleanup
+// func removes the directory.
+func whitelistedPathDir(grants []toolGrant) (dir string, cleanup func()) {
+	dir, err := os.MkdirTemp("", "workwise-exec-path-*")
+	if err != nil {
+		return "", func() {}
+	}
+
+	for _, g := range grants {
+		if g.Binary == "" {
+			continue
+		}
+		target, err := exec.LookPath(g.Binary)
+		if err != nil {
+			continue
+		}
+		_ = os.Symlink(target, filepath.Join(dir, g.Binary))
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// wrapForSandbox prepends a detected Linux sandbox wrapper (bwrap or
+// nsjail) to command/args for filesystem/network isolation, if one is
+// present on PATH. It falls back to running command directly everywhere
+// else, including Linux hosts with neither wrapper installed. allowNetwork
+// must be true when the skill holds a WebFetch grant: bwrap's network
+// isolation (--unshare-net) takes down loopback along with everything
+// else, so a sandboxed skill can't reach even a localhost
+// WORKWISE_HTTP_PROXY unless its network namespace is left shared.
+func wrapForSandbox(command string, args []string, allowNetwork bool) (string, []string) {
+	if runtime.GOOS != "linux" {
+		return command, args
+	}
+
+	for _, wrapper := range sandboxWrappers {
+		path, err := exec.LookPath(wrapper)
+		if err != nil {
+			continue
+		}
+
+		switch wrapper {
+		case "bwrap":
+			wrapped := []string{"--ro-bind", "/", "/", "--dev", "/dev"}
+			if !allowNetwork {
+				wrapped = append(wrapped, "--unshare-net")
+			}
+			wrapped = append(wrapped, "--", command)
+			return path, append(wrapped, args...)
+		case "nsjail":
+			wrapped := append([]string{"-Mo", "--"}, command)
+			return path, append(wrapped, args...)
+		}
+	}
+
+	return command, args
+}
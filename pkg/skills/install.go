@@ -0,0 +1,343 @@
+package skills
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestFileName is the per-skill install record written by
+// InstallFromURL/InstallFromHub and checked by LoadSkill on every load.
+const manifestFileName = ".skill-manifest.json"
+
+// Manifest records provenance for an installed skill: where it came from,
+// a digest of its on-disk contents at install time, and who signed it.
+// LoadSkill recomputes the digest on every load and refuses to register a
+// skill whose contents no longer match, surfacing tampering instead of
+// silently trusting a modified skill.
+type Manifest struct {
+	OriginURL   string    `json:"origin_url"`
+	Digest      string    `json:"digest"` // hex-encoded SHA-256 over the skill directory
+	SignerKeyID string    `json:"signer_key_id"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// TrustedKeys maps a key ID to the ed25519 public key it names. The key ID
+// is the hex-encoded public key itself, since hubs don't assign separate
+// identifiers.
+type TrustedKeys map[string]ed25519.PublicKey
+
+// ParseTrustedKeys decodes ExtensionsConfig.SkillsTrustedKeys (hex-encoded
+// ed25519 public keys) into a TrustedKeys set.
+func ParseTrustedKeys(hexKeys []string) (TrustedKeys, error) {
+	keys := make(TrustedKeys, len(hexKeys))
+	for _, hk := range hexKeys {
+		raw, err := hex.DecodeString(hk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted key %q: %w", hk, err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid trusted key %q: want %d bytes, got %d", hk, ed25519.PublicKeySize, len(raw))
+		}
+		keys[hk] = ed25519.PublicKey(raw)
+	}
+	return keys, nil
+}
+
+// Pack produces a gzip-compressed tarball of dir's contents (expected to
+// contain at least a SKILL.md), suitable for signing and hub distribution.
+func Pack(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() == manifestFileName {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack skill directory %q: %w", dir, err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize tar stream: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize gzip stream: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// unpack extracts a gzip-compressed tarball (as produced by Pack) into destDir.
+func unpack(data []byte, destDir string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to open tarball: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tarball entry: %w", err)
+		}
+
+		target := filepath.Join(destDir, hdr.Name)
+		if !strings.HasPrefix(target, filepath.Clean(destDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("tarball entry %q escapes destination directory", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, content, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// InstallFromURL downloads a signed skill tarball plus its detached ed25519
+// signature (expected at url+".sig"), verifies the signature against
+// trusted, and unpacks it into destDir/<skill-name> only if verification
+// succeeds. It registers the skill in the loader and writes a manifest
+// recording provenance so future loads can detect tampering.
+func (l *Loader) InstallFromURL(url, destDir string, trusted TrustedKeys) (*Skill, error) {
+	tarball, err := download(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download skill from %q: %w", url, err)
+	}
+
+	sig, err := download(url + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download signature for %q: %w", url, err)
+	}
+
+	keyID, err := verifySignature(tarball, sig, trusted)
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed for %q: %w", url, err)
+	}
+
+	// Unpack into a staging directory first so we can read the skill name
+	// from SKILL.md before choosing its final install path. Staging lives
+	// under destDir itself (not the system temp directory) so the rename
+	// below is always same-filesystem and can't fail with EXDEV partway
+	// through replacing an existing install.
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create skills directory %q: %w", destDir, err)
+	}
+	staging, err := os.MkdirTemp(destDir, ".skill-staging-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := unpack(tarball, staging); err != nil {
+		return nil, err
+	}
+
+	// Parse directly rather than via LoadSkill: the staging directory has a
+	// random temp name, so LoadSkill's name-matches-directory check would
+	// always fail here. The install directory below is named after the
+	// parsed skill, so that check holds there by construction.
+	skillMD, err := os.ReadFile(filepath.Join(staging, "SKILL.md"))
+	if err != nil {
+		return nil, fmt.Errorf("downloaded tarball has no SKILL.md: %w", err)
+	}
+	skill, err := ParseSkill(string(skillMD))
+	if err != nil {
+		return nil, fmt.Errorf("downloaded tarball is not a valid skill: %w", err)
+	}
+
+	installDir := filepath.Join(destDir, skill.Name)
+
+	// Move any existing install out of the way rather than deleting it
+	// outright, so a failed rename below (staging and installDir are both
+	// under destDir, so this should only fail if the install path itself
+	// is unwritable) leaves the previous working skill recoverable instead
+	// of wiped.
+	var previous string
+	if _, err := os.Stat(installDir); err == nil {
+		previous = installDir + ".replaced-" + filepath.Base(staging)
+		if err := os.Rename(installDir, previous); err != nil {
+			return nil, fmt.Errorf("failed to move aside existing install at %q: %w", installDir, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to stat existing install at %q: %w", installDir, err)
+	}
+
+	if err := os.Rename(staging, installDir); err != nil {
+		if previous != "" {
+			_ = os.Rename(previous, installDir) // best-effort restore of the prior install
+		}
+		return nil, fmt.Errorf("failed to install skill to %q: %w", installDir, err)
+	}
+	if previous != "" {
+		if err := os.RemoveAll(previous); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove replaced install at %q: %v\n", previous, err)
+		}
+	}
+	skill.SkillPath = installDir
+
+	digest, err := hashDir(installDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to digest installed skill: %w", err)
+	}
+
+	manifest := Manifest{
+		OriginURL:   url,
+		Digest:      digest,
+		SignerKeyID: keyID,
+		InstalledAt: time.Now(),
+	}
+	if err := writeManifest(installDir, manifest); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.skills[skill.Name] = skill
+	l.mu.Unlock()
+
+	return skill, nil
+}
+
+// InstallFromHub resolves name against hubURL (base URL of a skill hub,
+// serving "<name>.tar.gz" and "<name>.tar.gz.sig") and installs it via
+// InstallFromURL.
+func (l *Loader) InstallFromHub(name, hubURL, destDir string, trusted TrustedKeys) (*Skill, error) {
+	url := strings.TrimSuffix(hubURL, "/") + "/" + name + ".tar.gz"
+	return l.InstallFromURL(url, destDir, trusted)
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func verifySignature(data, sig []byte, trusted TrustedKeys) (string, error) {
+	for keyID, pub := range trusted {
+		if ed25519.Verify(pub, data, sig) {
+			return keyID, nil
+		}
+	}
+	return "", fmt.Errorf("no trusted key verifies this signature")
+}
+
+// hashDir computes a SHA-256 digest over every file under dir (excluding
+// the manifest itself), keyed by relative path so renames are detected.
+func hashDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() == manifestFileName {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash skill directory %q: %w", dir, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeManifest(skillDir string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(skillDir, manifestFileName), data, 0644)
+}
+
+// readManifest returns the manifest for skillDir, or nil if the skill was
+// not installed through InstallFromURL/InstallFromHub.
+func readManifest(skillDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(skillDir, manifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse install manifest: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,115 @@
+package skills
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestSkill(t *testing.T, dir, name string) {
+	t.Helper()
+	content := "---\nname: " + name + "\ndescription: A test skill\n---\n\n# Test Skill\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	writeTestSkill(t, srcDir, "pack-test")
+
+	tarball, err := Pack(srcDir)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	destDir := t.TempDir()
+	if err := unpack(tarball, destDir); err != nil {
+		t.Fatalf("unpack failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(destDir, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("unpacked SKILL.md missing: %v", err)
+	}
+	if !strings.Contains(string(data), "pack-test") {
+		t.Error("unpacked SKILL.md should contain the original skill name")
+	}
+}
+
+func TestHashDirDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	writeTestSkill(t, dir, "hash-test")
+
+	digest, err := hashDir(dir)
+	if err != nil {
+		t.Fatalf("hashDir failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("tampered"), 0644); err != nil {
+		t.Fatalf("failed to tamper with SKILL.md: %v", err)
+	}
+
+	tamperedDigest, err := hashDir(dir)
+	if err != nil {
+		t.Fatalf("hashDir failed: %v", err)
+	}
+
+	if digest == tamperedDigest {
+		t.Error("expected digest to change after tampering")
+	}
+}
+
+func TestLoadSkillRejectsTamperedManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillDir := filepath.Join(tmpDir, "manifest-test")
+	if err := os.Mkdir(skillDir, 0755); err != nil {
+		t.Fatalf("failed to create skill directory: %v", err)
+	}
+	writeTestSkill(t, skillDir, "manifest-test")
+
+	digest, err := hashDir(skillDir)
+	if err != nil {
+		t.Fatalf("hashDir failed: %v", err)
+	}
+	if err := writeManifest(skillDir, Manifest{OriginURL: "https://example.com/s.tar.gz", Digest: digest}); err != nil {
+		t.Fatalf("writeManifest failed: %v", err)
+	}
+
+	if _, err := LoadSkill(skillDir); err != nil {
+		t.Fatalf("LoadSkill should succeed when contents match the manifest: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: manifest-test\ndescription: tampered\n---\n"), 0644); err != nil {
+		t.Fatalf("failed to tamper with SKILL.md: %v", err)
+	}
+
+	if _, err := LoadSkill(skillDir); err == nil {
+		t.Error("expected LoadSkill to refuse a skill whose contents no longer match its manifest")
+	}
+}
+
+func TestParseTrustedKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	keys, err := ParseTrustedKeys([]string{hex.EncodeToString(pub)})
+	if err != nil {
+		t.Fatalf("ParseTrustedKeys failed: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("expected 1 trusted key, got %d", len(keys))
+	}
+
+	if _, err := ParseTrustedKeys([]string{"not-hex"}); err == nil {
+		t.Error("expected error for invalid hex key")
+	}
+	if _, err := ParseTrustedKeys([]string{"aabb"}); err == nil {
+		t.Error("expected error for key of the wrong length")
+	}
+}
@@ -0,0 +1,226 @@
+package skills
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RemoteSource fetches a skill search path from somewhere other than the
+// local filesystem. Sync is called once by Loader.LoadAll and, for sources
+// registered with a nonzero interval, again periodically by Loader.Watch.
+type RemoteSource interface {
+	// Sync ensures the source's content is present locally and returns the
+	// directory Loader should treat as a normal search path.
+	Sync(ctx context.Context) (string, error)
+}
+
+// remoteEntry pairs a registered RemoteSource with the refresh interval
+// Loader.Watch should poll it at.
+type remoteEntry struct {
+	source   RemoteSource
+	interval time.Duration
+}
+
+// AddRemoteSource registers src so its content is pulled on every LoadAll
+// and, if interval is positive, re-pulled on that cadence by Watch.
+func (l *Loader) AddRemoteSource(src RemoteSource, interval time.Duration) {
+	l.remotesMu.Lock()
+	defer l.remotesMu.Unlock()
+	l.remotes = append(l.remotes, &remoteEntry{source: src, interval: interval})
+}
+
+// syncRemotes calls Sync on every registered RemoteSource and returns the
+// resulting search paths.
+func (l *Loader) syncRemotes(ctx context.Context) ([]string, error) {
+	l.remotesMu.Lock()
+	remotes := make([]*remoteEntry, len(l.remotes))
+	copy(remotes, l.remotes)
+	l.remotesMu.Unlock()
+
+	paths := make([]string, 0, len(remotes))
+	for _, r := range remotes {
+		dir, err := r.source.Sync(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sync remote skill source: %w", err)
+		}
+		paths = append(paths, dir)
+	}
+	return paths, nil
+}
+
+// GitSource is a RemoteSource that clones/pulls a git repository into a
+// per-source cache directory and treats it (or a subdirectory of it) as a
+// skill search path, the way service discovery pins a trusted target set.
+type GitSource struct {
+	// URL is the git remote to clone, e.g. "https://github.com/org/skills.git".
+	URL string
+
+	// Ref is the branch, tag, or commit to check out. Empty means the
+	// repository's default branch.
+	Ref string
+
+	// Subpath, if set, is the directory within the repository to use as the
+	// search root instead of the repository root.
+	Subpath string
+
+	// ExpectedChecksum, if set, is the hex-encoded SHA-256 digest (as
+	// computed by hashDir) the synced content must match; Sync fails
+	// otherwise, refusing to trust tampered or unexpected remote content.
+	ExpectedChecksum string
+
+	// TrustedKeys, if non-empty, requires a detached ed25519 signature over
+	// the synced content's digest, at SignaturePath relative to the
+	// repository root, from one of these keys.
+	TrustedKeys TrustedKeys
+
+	// SignaturePath is where the detached signature is read from when
+	// TrustedKeys is set. Defaults to "manifest.sig".
+	SignaturePath string
+
+	// CacheDir overrides the default ~/.workwise/skills-cache root. Mainly
+	// useful for tests.
+	CacheDir string
+}
+
+// NewGitSource creates a GitSource that clones/pulls url's default branch.
+func NewGitSource(url string) *GitSource {
+	return &GitSource{URL: url}
+}
+
+// Sync implements RemoteSource: it clones the repository on first use and
+// pulls on every subsequent call, then verifies the checksum/signature (if
+// configured) before returning the search directory.
+func (g *GitSource) Sync(ctx context.Context) (string, error) {
+	cacheRoot := g.CacheDir
+	if cacheRoot == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve home directory for skills cache: %w", err)
+		}
+		cacheRoot = filepath.Join(home, ".workwise", "skills-cache")
+	}
+
+	dir := filepath.Join(cacheRoot, g.cacheKey())
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := g.clone(ctx, dir); err != nil {
+			return "", err
+		}
+	} else {
+		if err := g.pull(ctx, dir); err != nil {
+			return "", err
+		}
+	}
+
+	if err := g.verify(dir); err != nil {
+		return "", err
+	}
+
+	searchDir := dir
+	if g.Subpath != "" {
+		searchDir = filepath.Join(dir, g.Subpath)
+	}
+	return searchDir, nil
+}
+
+// cacheKey derives the per-source cache directory name from everything that
+// identifies what content should be there, so a ref/subpath change doesn't
+// silently reuse a stale clone.
+func (g *GitSource) cacheKey() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", g.URL, g.Ref, g.Subpath)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// clone clones g.URL into dir. If Ref is set, it's resolved the same way
+// pull updates an existing clone (init, fetch Ref, checkout FETCH_HEAD)
+// rather than via `git clone --branch`, which only accepts a branch or tag
+// name, not a commit SHA, even though Ref documents all three as valid.
+func (g *GitSource) clone(ctx context.Context, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create skills cache directory: %w", err)
+	}
+
+	if g.Ref == "" {
+		if out, err := exec.CommandContext(ctx, "git", "clone", "--depth", "1", g.URL, dir).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to clone %q: %w: %s", g.URL, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if out, err := exec.CommandContext(ctx, "git", "init", dir).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to init %q: %w: %s", dir, err, strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "remote", "add", "origin", g.URL).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add remote %q in %q: %w: %s", g.URL, dir, err, strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "fetch", "--depth", "1", "origin", g.Ref).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch %q in %q: %w: %s", g.Ref, dir, err, strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "checkout", "FETCH_HEAD").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to checkout %q in %q: %w: %s", g.Ref, dir, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (g *GitSource) pull(ctx context.Context, dir string) error {
+	if g.Ref != "" {
+		if out, err := exec.CommandContext(ctx, "git", "-C", dir, "fetch", "--depth", "1", "origin", g.Ref).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to fetch %q in %q: %w: %s", g.Ref, dir, err, strings.TrimSpace(string(out)))
+		}
+		if out, err := exec.CommandContext(ctx, "git", "-C", dir, "checkout", "FETCH_HEAD").CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to checkout %q in %q: %w: %s", g.Ref, dir, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "pull", "--ff-only").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to pull %q: %w: %s", dir, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// verify checks ExpectedChecksum/TrustedKeys against dir's freshly-synced
+// content, if either is configured.
+func (g *GitSource) verify(dir string) error {
+	if g.ExpectedChecksum == "" && len(g.TrustedKeys) == 0 {
+		return nil
+	}
+
+	digest, err := hashDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to digest synced source %q: %w", g.URL, err)
+	}
+
+	if g.ExpectedChecksum != "" && digest != g.ExpectedChecksum {
+		return fmt.Errorf("remote skill source %q failed checksum verification: refusing to trust", g.URL)
+	}
+
+	if len(g.TrustedKeys) == 0 {
+		return nil
+	}
+
+	sigPath := g.SignaturePath
+	if sigPath == "" {
+		sigPath = "manifest.sig"
+	}
+	sig, err := os.ReadFile(filepath.Join(dir, sigPath))
+	if err != nil {
+		return fmt.Errorf("remote skill source %q has no manifest signature at %q: %w", g.URL, sigPath, err)
+	}
+
+	for _, pub := range g.TrustedKeys {
+		if ed25519.Verify(pub, []byte(digest), sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("remote skill source %q: no trusted key verifies the manifest signature", g.URL)
+}
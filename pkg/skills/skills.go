@@ -1,10 +1,12 @@
 package skills
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
@@ -31,8 +33,13 @@ type Skill struct {
 
 // Loader manages loading and discovering skills from the filesystem
 type Loader struct {
-	skillPaths []string          // Directories to search for skills
-	skills     map[string]*Skill // Loaded skills indexed by name
+	skillPaths []string // Directories to search for skills
+
+	mu     sync.RWMutex
+	skills map[string]*Skill // Loaded skills indexed by name
+
+	remotesMu sync.Mutex
+	remotes   []*remoteEntry // Registered RemoteSources, synced on every LoadAll
 }
 
 // NewLoader creates a new skills loader with the given search paths
@@ -43,16 +50,22 @@ func NewLoader(paths []string) *Loader {
 	}
 }
 
-// LoadAll discovers and loads all skills from the configured paths
+// LoadAll discovers and loads all skills from the configured paths plus any
+// registered RemoteSources, replacing whatever was previously loaded. It's
+// safe to call repeatedly on the same Loader, e.g. from a config reload.
 func (l *Loader) LoadAll() error {
-	for _, basePath := range l.skillPaths {
-		// Expand home directory if needed
-		if strings.HasPrefix(basePath, "~/") {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return fmt.Errorf("failed to expand home directory: %w", err)
-			}
-			basePath = filepath.Join(home, basePath[2:])
+	searchPaths, err := l.syncRemotes(context.Background())
+	if err != nil {
+		return err
+	}
+	searchPaths = append(l.skillPaths, searchPaths...)
+
+	skills := make(map[string]*Skill)
+
+	for _, basePath := range searchPaths {
+		basePath, err := expandHome(basePath)
+		if err != nil {
+			return err
 		}
 
 		// Check if path exists
@@ -74,10 +87,10 @@ func (l *Loader) LoadAll() error {
 				}
 
 				// Register the skill
-				if _, exists := l.skills[skill.Name]; exists {
+				if _, exists := skills[skill.Name]; exists {
 					return fmt.Errorf("duplicate skill name: %s", skill.Name)
 				}
-				l.skills[skill.Name] = skill
+				skills[skill.Name] = skill
 			}
 
 			return nil
@@ -88,11 +101,18 @@ func (l *Loader) LoadAll() error {
 		}
 	}
 
+	l.mu.Lock()
+	l.skills = skills
+	l.mu.Unlock()
+
 	return nil
 }
 
 // Get retrieves a loaded skill by name
 func (l *Loader) Get(name string) (*Skill, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	skill, exists := l.skills[name]
 	if !exists {
 		return nil, fmt.Errorf("skill not found: %s", name)
@@ -102,6 +122,9 @@ func (l *Loader) Get(name string) (*Skill, error) {
 
 // List returns all loaded skill names
 func (l *Loader) List() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	names := make([]string, 0, len(l.skills))
 	for name := range l.skills {
 		names = append(names, name)
@@ -109,8 +132,37 @@ func (l *Loader) List() []string {
 	return names
 }
 
+// expandHome expands a leading "~/" in path to the user's home directory.
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to expand home directory: %w", err)
+	}
+	return filepath.Join(home, path[2:]), nil
+}
+
+// expandedSkillPaths returns skillPaths with "~/" expanded, skipping any
+// entry whose home directory can't be resolved.
+func (l *Loader) expandedSkillPaths() []string {
+	paths := make([]string, 0, len(l.skillPaths))
+	for _, p := range l.skillPaths {
+		expanded, err := expandHome(p)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, expanded)
+	}
+	return paths
+}
+
 // GetAll returns all loaded skills
 func (l *Loader) GetAll() []*Skill {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	skills := make([]*Skill, 0, len(l.skills))
 	for _, skill := range l.skills {
 		skills = append(skills, skill)
@@ -143,6 +195,24 @@ func LoadSkill(skillDir string) (*Skill, error) {
 		return nil, fmt.Errorf("skill name '%s' does not match directory name '%s'", skill.Name, dirName)
 	}
 
+	// Skills installed via InstallFromURL/InstallFromHub carry a manifest
+	// recording the digest of their contents at install time; refuse to
+	// register one whose on-disk content no longer matches, since that
+	// means it was tampered with (or corrupted) after installation.
+	manifest, err := readManifest(skillDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install manifest: %w", err)
+	}
+	if manifest != nil {
+		digest, err := hashDir(skillDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify skill integrity: %w", err)
+		}
+		if digest != manifest.Digest {
+			return nil, fmt.Errorf("skill '%s' failed integrity check: contents do not match install manifest, refusing to register", skill.Name)
+		}
+	}
+
 	return skill, nil
 }
 
@@ -0,0 +1,233 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceInterval coalesces bursts of filesystem events (e.g. an editor
+// writing a file in several steps) into a single reload, mirroring
+// config.Watcher's debounce.
+const debounceInterval = 250 * time.Millisecond
+
+// SkillEventType describes what happened to a skill in a Loader.Watch event.
+type SkillEventType string
+
+const (
+	SkillAdded   SkillEventType = "added"
+	SkillUpdated SkillEventType = "updated"
+	SkillRemoved SkillEventType = "removed"
+)
+
+// SkillEvent reports a skill added, changed, or removed while Loader.Watch
+// is running, so subscribers (e.g. an agent's prompt cache) can react to a
+// hot-reloaded skill without polling GetAll.
+type SkillEvent struct {
+	Type  SkillEventType
+	Name  string
+	Skill *Skill // nil for SkillRemoved
+}
+
+// Watch starts watching every configured skill path (and every directory
+// synced from a registered RemoteSource) for changes, re-parsing and
+// atomically swapping in the affected skill whenever its SKILL.md, or any
+// file under its directory, is created, modified, or removed. RemoteSources
+// registered with a nonzero interval are also re-synced on that cadence.
+// Watch runs until ctx is cancelled; the returned channel is closed once it
+// stops.
+func (l *Loader) Watch(ctx context.Context) (<-chan SkillEvent, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filesystem watcher: %w", err)
+	}
+
+	for _, path := range l.expandedSkillPaths() {
+		addRecursive(fsw, path)
+	}
+
+	l.remotesMu.Lock()
+	remotes := make([]*remoteEntry, len(l.remotes))
+	copy(remotes, l.remotes)
+	l.remotesMu.Unlock()
+
+	for _, r := range remotes {
+		if dir, err := r.source.Sync(ctx); err == nil {
+			addRecursive(fsw, dir)
+		}
+	}
+
+	events := make(chan SkillEvent, 16)
+	go l.watchLoop(ctx, fsw, remotes, events)
+
+	return events, nil
+}
+
+func (l *Loader) watchLoop(ctx context.Context, fsw *fsnotify.Watcher, remotes []*remoteEntry, events chan SkillEvent) {
+	defer close(events)
+	defer fsw.Close()
+
+	var mu sync.Mutex
+	dirty := make(map[string]struct{})
+	var timer *time.Timer
+
+	flush := func() {
+		mu.Lock()
+		dirs := make([]string, 0, len(dirty))
+		for d := range dirty {
+			dirs = append(dirs, d)
+		}
+		dirty = make(map[string]struct{})
+		mu.Unlock()
+
+		for _, dir := range dirs {
+			l.reloadSkillDir(dir, events)
+		}
+	}
+
+	markDirty := func(dir string) {
+		mu.Lock()
+		dirty[dir] = struct{}{}
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(debounceInterval, flush)
+		mu.Unlock()
+	}
+
+	for _, r := range remotes {
+		if r.interval <= 0 {
+			continue
+		}
+		go l.pollRemote(ctx, r, fsw, markDirty)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Remove) != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					addRecursive(fsw, event.Name)
+				}
+			}
+			if dir := l.skillDirForPath(event.Name); dir != "" {
+				markDirty(dir)
+			}
+		case _, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// pollRemote re-syncs r on its configured interval until ctx is cancelled,
+// marking the synced directory dirty on every successful sync so its
+// skills get re-parsed even if the source doesn't generate fs events
+// (e.g. a fresh git clone replacing files atomically).
+func (l *Loader) pollRemote(ctx context.Context, r *remoteEntry, fsw *fsnotify.Watcher, markDirty func(string)) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dir, err := r.source.Sync(ctx)
+			if err != nil {
+				continue
+			}
+			addRecursive(fsw, dir)
+			markDirty(dir)
+		}
+	}
+}
+
+// skillDirForPath returns the skill directory path belongs to: its parent
+// if path is a SKILL.md, or the SkillPath of whichever loaded skill it
+// falls under otherwise. Returns "" if path isn't part of any skill.
+func (l *Loader) skillDirForPath(path string) string {
+	if filepath.Base(path) == "SKILL.md" {
+		return filepath.Dir(path)
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, skill := range l.skills {
+		if strings.HasPrefix(path, skill.SkillPath+string(os.PathSeparator)) {
+			return skill.SkillPath
+		}
+	}
+	return ""
+}
+
+// reloadSkillDir re-parses the skill at dir and atomically swaps it into
+// the skills map, or removes it if its SKILL.md no longer exists. A parse
+// failure leaves the previous version registered, since a bad in-progress
+// edit shouldn't drop a working skill.
+func (l *Loader) reloadSkillDir(dir string, events chan<- SkillEvent) {
+	if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); os.IsNotExist(err) {
+		l.removeSkillByDir(dir, events)
+		return
+	}
+
+	skill, err := LoadSkill(dir)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	_, existed := l.skills[skill.Name]
+	l.skills[skill.Name] = skill
+	l.mu.Unlock()
+
+	eventType := SkillUpdated
+	if !existed {
+		eventType = SkillAdded
+	}
+	events <- SkillEvent{Type: eventType, Name: skill.Name, Skill: skill}
+}
+
+// removeSkillByDir removes whichever loaded skill has SkillPath == dir.
+func (l *Loader) removeSkillByDir(dir string, events chan<- SkillEvent) {
+	l.mu.Lock()
+	var name string
+	for n, skill := range l.skills {
+		if skill.SkillPath == dir {
+			name = n
+			break
+		}
+	}
+	if name != "" {
+		delete(l.skills, name)
+	}
+	l.mu.Unlock()
+
+	if name != "" {
+		events <- SkillEvent{Type: SkillRemoved, Name: name}
+	}
+}
+
+// addRecursive adds root and every directory beneath it to fsw, best-effort
+// (fsnotify has no native recursive watch).
+func addRecursive(fsw *fsnotify.Watcher, root string) {
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		_ = fsw.Add(path)
+		return nil
+	})
+}